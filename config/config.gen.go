@@ -17,17 +17,45 @@ import (
 	"github.com/orsinium-labs/enum"
 )
 
+// Defines values for EmailSecurity.
+var (
+	emailSecurityBuilder = enum.NewBuilder[string, EmailSecurity]()
+
+	EmailSecurityNone     = emailSecurityBuilder.Add(EmailSecurity{"none"})
+	EmailSecurityStarttls = emailSecurityBuilder.Add(EmailSecurity{"starttls"})
+	EmailSecurityTls      = emailSecurityBuilder.Add(EmailSecurity{"tls"})
+
+	EmailSecuritys = emailSecurityBuilder.Enum()
+)
+
 // Defines values for NotificationType.
 var (
 	notificationTypeBuilder = enum.NewBuilder[string, NotificationType]()
 
+	NotificationTypeDiscord  = notificationTypeBuilder.Add(NotificationType{"discord"})
+	NotificationTypeEmail    = notificationTypeBuilder.Add(NotificationType{"email"})
+	NotificationTypeExec     = notificationTypeBuilder.Add(NotificationType{"exec"})
 	NotificationTypeGotify   = notificationTypeBuilder.Add(NotificationType{"gotify"})
+	NotificationTypeMqtt     = notificationTypeBuilder.Add(NotificationType{"mqtt"})
 	NotificationTypeNtfy     = notificationTypeBuilder.Add(NotificationType{"ntfy"})
+	NotificationTypePushover = notificationTypeBuilder.Add(NotificationType{"pushover"})
+	NotificationTypeSlack    = notificationTypeBuilder.Add(NotificationType{"slack"})
 	NotificationTypeTelegram = notificationTypeBuilder.Add(NotificationType{"telegram"})
+	NotificationTypeWebhook  = notificationTypeBuilder.Add(NotificationType{"webhook"})
 
 	NotificationTypes = notificationTypeBuilder.Enum()
 )
 
+// Defines values for TelegramParseMode.
+var (
+	telegramParseModeBuilder = enum.NewBuilder[string, TelegramParseMode]()
+
+	TelegramParseModeHtml       = telegramParseModeBuilder.Add(TelegramParseMode{"html"})
+	TelegramParseModeMarkdownv2 = telegramParseModeBuilder.Add(TelegramParseMode{"markdownv2"})
+
+	TelegramParseModes = telegramParseModeBuilder.Enum()
+)
+
 // Config defines model for Config.
 type Config struct {
 	// APIKey REQUIRED: See README.md for details on how to obtain
@@ -37,19 +65,110 @@ type Config struct {
 	// Currently only GB is supported.
 	Country Country `json:"country"`
 
+	// Countries Additional countries to scan for tickets, alongside country (Optional).
+	Countries []Country `json:"countries,omitempty"`
+
+	// ScanInterval How often to scan for new ticket listings e.g. 30s, 1m.
+	// Must be at least 5s. Changes take effect without a restart.
+	// Default: 1m.
+	ScanInterval Duration `json:"scanInterval,omitempty"`
+
+	// ScanJitter Maximum random time added to each scan interval, to avoid scanning at regular times e.g. 10s.
+	// Default: No jitter.
+	ScanJitter Duration `json:"scanJitter,omitempty"`
+
+	// ScanMaxBackoff Maximum time to wait between scans when scanning keeps failing.
+	// The time between scans doubles after each consecutive failure, up to this maximum.
+	// Default: 15m.
+	ScanMaxBackoff Duration `json:"scanMaxBackoff,omitempty"`
+
 	// FlaresolverrUrl URL of FlareSolverr proxy server for bypassing Cloudflare (Optional)
 	FlaresolverrUrl string `json:"flaresolverrUrl,omitempty"`
 
-	// Notification Notification service configuration
-	Notification       NotificationConfig        `json:"notification"`
+	// StatePath Path of the file used to store state that must survive restarts,
+	// such as listings that have already been notified (Optional, Requires restart).
+	// Default: state.db in the working directory.
+	StatePath string `json:"statePath,omitempty"`
+
+	// Paused Whether notifications are paused (Optional).
+	// Tickets are still scanned for while paused, but no notifications are sent for them.
+	Paused bool `json:"paused,omitempty"`
+
+	// Notification Notification services, one of each type (Optional).
+	// Each service is a notifier named after its type e.g. ntfy.
+	// Use notifiers to configure more than one service of the same type.
+	Notification NotificationConfig `json:"notification,omitzero"`
+
+	// NotifierConfigs Named notification services (Optional).
+	// Names must be unique, including the names of notification services configured in notification.
+	NotifierConfigs    []NotifierConfig          `json:"notifiers,omitempty"`
 	GlobalTicketConfig GlobalTicketListingConfig `json:"global"`
 	TicketConfigs      []TicketListingConfig     `json:"tickets"`
 }
 
+// Countries defines model for Countries.
+type Countries []Country
+
 // Country Country code.
 // Currently only GB is supported.
 type Country = twigots.Country
 
+// DiscordConfig defines model for DiscordConfig.
+type DiscordConfig struct {
+	// WebhookUrl Discord webhook URL. Create one in the integrations settings of a channel
+	WebhookUrl string `json:"webhookUrl"`
+
+	// Username Name to send messages as, instead of the webhook name (Optional)
+	Username string `json:"username,omitempty"`
+}
+
+// EmailConfig defines model for EmailConfig.
+type EmailConfig struct {
+	// Host SMTP server host
+	Host string `json:"host"`
+
+	// Port SMTP server port e.g. 587 for STARTTLS, 465 for TLS
+	Port int `json:"port"`
+
+	// Security How to secure the connection to the SMTP server (Optional).
+	// Default: starttls.
+	Security EmailSecurity `json:"security,omitzero"`
+
+	// Username Username for authenticated SMTP servers (Optional)
+	Username string `json:"username,omitempty"`
+
+	// Password Password for authenticated SMTP servers (Optional)
+	Password string `json:"password,omitempty"`
+
+	// From Address to send emails from
+	From string `json:"from"`
+
+	// To Addresses to send emails to
+	To []string `json:"to"`
+}
+
+// EmailSecurity How to secure the connection to an SMTP server.
+//   - none: Do not secure the connection. Only use this for servers on your own network.
+//     A username and password can only be used with this if the host is localhost.
+//   - starttls: Upgrade the connection to TLS using STARTTLS.
+//   - tls: Connect using TLS (implicit TLS).
+type EmailSecurity enum.Member[string]
+
+// ExecConfig defines model for ExecConfig.
+type ExecConfig struct {
+	// Command Command to run e.g. /scripts/notify.sh.
+	// The command is run directly, not in a shell.
+	Command string `json:"command"`
+
+	// Args Arguments to run the command with (Optional)
+	Args []string `json:"args,omitempty"`
+
+	// Timeout Maximum time the command can run for before it is stopped e.g. 10s (Optional).
+	// Cannot be longer than the timeout of the notifier.
+	// Default: 30s.
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
 // GlobalTicketListingConfig GlobalTicketListingConfig represents configuration settings that apply to all ticket listings
 // unless explicitly overridden by a specific ticket configuration.
 // Any setting not specified will use the default.
@@ -58,7 +177,13 @@ type GlobalTicketListingConfig struct {
 	// Default: 0.9 (allows for minor naming differences)
 	EventSimilarity float64 `json:"eventSimilarity,omitempty"`
 
+	// Countries Countries to search for tickets.
+	// Countries must be scanned, i.e. be either country or one of countries.
+	// Default: All scanned countries if not specified.
+	Countries []Country `json:"countries,omitempty"`
+
 	// Regions Geographic regions to search for tickets.
+	// Regions must be in one of the countries being searched.
 	// Default: All regions if not specified.
 	// Full list: https://github.com/ahobsonsayers/twigots/blob/main/location.go#L79-L90
 	Regions []Region `json:"regions,omitempty"`
@@ -75,9 +200,15 @@ type GlobalTicketListingConfig struct {
 	// Default: Any price.
 	MaxTicketPriceInclFee float64 `json:"maxTicketPrice,omitempty"`
 
-	// Notification Notification services to use
-	// Default: All configured services.
-	Notification []NotificationType `json:"notification,omitempty"`
+	// Notification Names of notifiers to use
+	// Default: All configured notifiers.
+	Notification []string `json:"notification,omitempty"`
+
+	// RoutingRules Rules choosing the notifiers to use for listings with a discount or price.
+	// Rules are checked in order, and the notifiers of the first rule the listing matches are used.
+	// If the listing does not match any rule, notification is used.
+	// Default: No rules.
+	RoutingRules RoutingRules `json:"routingRules,omitempty"`
 }
 
 // GotifyConfig defines model for GotifyConfig.
@@ -85,8 +216,52 @@ type GotifyConfig struct {
 	// Url Your Gotify server URL
 	Url string `json:"url"`
 
-	// Token Application token from Gotify
+	// Token Application token from Gotify.
+	// The token is checked when twitchets starts, or the config is reloaded.
 	Token string `json:"token"`
+
+	// Priority Priority of notifications, from 0 (min) to 10 (max) (Optional).
+	// Default: 5.
+	Priority *int `json:"priority,omitempty"`
+
+	// PriorityRules Priorities of notifications for listings with a discount or price (Optional).
+	// The first rule the listing matches is used, otherwise priority is used.
+	PriorityRules []PriorityRule `json:"priorityRules,omitempty"`
+}
+
+// MqttConfig defines model for MqttConfig.
+type MqttConfig struct {
+	// Broker MQTT broker URL e.g. tcp://localhost:1883.
+	// Use ssl:// for TLS, or ws:// and wss:// for websockets.
+	Broker string `json:"broker"`
+
+	// Topic Topic to publish matches to
+	Topic string `json:"topic"`
+
+	// ClientId Client ID to connect with (Optional).
+	// Default: twitchets.
+	ClientId string `json:"clientId,omitempty"`
+
+	// Username Username for authenticated brokers (Optional)
+	Username string `json:"username,omitempty"`
+
+	// Password Password for authenticated brokers (Optional)
+	Password string `json:"password,omitempty"`
+
+	// Qos Quality of service to publish with. One of 0, 1 or 2 (Optional).
+	// Default: 0.
+	Qos int `json:"qos,omitempty"`
+
+	// Retain Whether the broker should retain published matches (Optional)
+	Retain bool `json:"retain,omitempty"`
+
+	// HomeAssistantDiscovery Whether to publish Home Assistant MQTT discovery configs (Optional).
+	// This creates a "last match" sensor for each event.
+	HomeAssistantDiscovery bool `json:"homeAssistantDiscovery,omitempty"`
+
+	// DiscoveryPrefix Home Assistant MQTT discovery prefix (Optional).
+	// Default: homeassistant.
+	DiscoveryPrefix string `json:"discoveryPrefix,omitempty"`
 }
 
 // NotificationConfig Notification service configuration
@@ -100,7 +275,65 @@ type NotificationConfig struct {
 type NotificationType enum.Member[string]
 
 // Notifications defines model for Notifications.
-type Notifications []NotificationType
+type Notifications []string
+
+// NotifierConfig A named notification service.
+// Either type and the settings of the type, or url should be set.
+type NotifierConfig struct {
+	// Name Unique name of the notifier, used to choose which notifiers to use for tickets
+	Name string `json:"name"`
+
+	// Type Type of the notification service.
+	// Cannot be used with url.
+	Type NotificationType `json:"type,omitzero"`
+
+	// Url URL of the notification service, instead of type and its settings e.g. pushover://user@token.
+	// Cannot be used with type. See README.md for the supported URLs.
+	Url      string          `json:"url,omitempty"`
+	Ntfy     *NtfyConfig     `json:"ntfy,omitempty"`
+	Gotify   *GotifyConfig   `json:"gotify,omitempty"`
+	Telegram *TelegramConfig `json:"telegram,omitempty"`
+	Webhook  *WebhookConfig  `json:"webhook,omitempty"`
+	Discord  *DiscordConfig  `json:"discord,omitempty"`
+	Slack    *SlackConfig    `json:"slack,omitempty"`
+	Email    *EmailConfig    `json:"email,omitempty"`
+	Mqtt     *MqttConfig     `json:"mqtt,omitempty"`
+	Exec     *ExecConfig     `json:"exec,omitempty"`
+	Pushover *PushoverConfig `json:"pushover,omitempty"`
+
+	// Template Go template used to render notification messages (Optional).
+	// Cannot be used with templateFile.
+	// Default: Built in template.
+	Template string `json:"template,omitempty"`
+
+	// TemplateFile Path of a file containing a Go template used to render notification messages (Optional).
+	// Cannot be used with template.
+	TemplateFile string `json:"templateFile,omitempty"`
+
+	// TitleTemplate Go template used to render notification titles (Optional).
+	// Not all notification types have titles.
+	// Default: Event name.
+	TitleTemplate string `json:"titleTemplate,omitempty"`
+
+	// DigestTemplate Go template used to render digests, which combine the notifications of several listings (Optional).
+	// Cannot be used by webhook, mqtt and exec notifiers, which send each listing on its own.
+	// Default: Built in template.
+	DigestTemplate string `json:"digestTemplate,omitempty"`
+
+	// QuietHours Hours when notifications are held rather than sent (Optional).
+	// Held notifications are sent as one digest when quiet hours end.
+	QuietHours *QuietHoursConfig `json:"quietHours,omitempty"`
+
+	// BatchWindow Time to wait after a listing for an event is found before notifying, e.g. 2m (Optional).
+	// Listings for the same event found while waiting are combined into one digest.
+	// Webhook, mqtt and exec notifiers send each listing on its own instead.
+	// Default: Notify straight away.
+	BatchWindow Duration `json:"batchWindow,omitempty"`
+
+	// Timeout Maximum time to wait for a notification to be sent, e.g. 1m (Optional).
+	// Default: 30s.
+	Timeout Duration `json:"timeout,omitempty"`
+}
 
 // NtfyConfig defines model for NtfyConfig.
 type NtfyConfig struct {
@@ -115,6 +348,75 @@ type NtfyConfig struct {
 
 	// Password Password for authenticated instances (Optional)
 	Password string `json:"password,omitempty"`
+
+	// Token Access token for authenticated instances e.g. tk_... (Optional).
+	// Use instead of username and password.
+	Token string `json:"token,omitempty"`
+
+	// Priority Priority of notifications, from 1 (min) to 5 (max) (Optional).
+	// Default: 3.
+	Priority int `json:"priority,omitempty"`
+
+	// PriorityRules Priorities of notifications for listings with a discount or price (Optional).
+	// The first rule the listing matches is used, otherwise priority is used.
+	PriorityRules []PriorityRule `json:"priorityRules,omitempty"`
+
+	// Tags Tags of notifications, which can be emojis e.g. tada (Optional)
+	Tags []string `json:"tags,omitempty"`
+
+	// Icon URL of an icon to show with notifications (Optional)
+	Icon string `json:"icon,omitempty"`
+
+	// Delay Time to delay notifications by e.g. 30m (Optional).
+	// Must be at least 10s.
+	Delay Duration `json:"delay,omitempty"`
+}
+
+// PriorityRule A priority used for listings matching all the conditions of the rule.
+// Conditions that are not set always match.
+type PriorityRule struct {
+	// Priority Priority to use
+	Priority int `json:"priority"`
+
+	// MinDiscount Minimum discount on the original price as a percentage (Optional)
+	MinDiscount float64 `json:"minDiscount,omitempty"`
+
+	// MinTicketPrice Minimum price per ticket (including fee) in pounds (£) (Optional)
+	MinTicketPrice float64 `json:"minTicketPrice,omitempty"`
+
+	// MaxTicketPrice Maximum price per ticket (including fee) in pounds (£) (Optional)
+	MaxTicketPrice float64 `json:"maxTicketPrice,omitempty"`
+}
+
+// PushoverConfig defines model for PushoverConfig.
+type PushoverConfig struct {
+	// User Your user or group key, shown on your Pushover dashboard
+	User string `json:"user"`
+
+	// Token API token of your Pushover application
+	Token string `json:"token"`
+
+	// Device Device to send notifications to (Optional).
+	// Default: All of your devices.
+	Device string `json:"device,omitempty"`
+}
+
+// QuietHoursConfig Hours when notifications are held rather than sent (Optional).
+// Held notifications are sent as one digest when quiet hours end.
+type QuietHoursConfig struct {
+	// Start Time quiet hours start, in 24 hour HH:MM format e.g. 22:00
+	Start string `json:"start"`
+
+	// End Time quiet hours end, in 24 hour HH:MM format e.g. 07:00
+	End string `json:"end"`
+
+	// Timezone Timezone of the start and end times e.g. Europe/London (Optional).
+	// Default: Local timezone.
+	Timezone string `json:"timezone,omitempty"`
+
+	// BypassDiscount Minimum discount on the original price as a percentage for listings to be sent during quiet hours (Optional).
+	// Default: All listings are held.
+	BypassDiscount float64 `json:"bypassDiscount,omitempty"`
 }
 
 // Region Region code.
@@ -136,15 +438,72 @@ type Region = twigots.Region
 // Regions defines model for Regions.
 type Regions []Region
 
+// RoutingRule A rule choosing the notifiers to use for listings matching all the conditions of the rule.
+// Conditions that are not set always match.
+type RoutingRule struct {
+	// MinDiscount Minimum discount on the original price as a percentage (Optional)
+	MinDiscount float64 `json:"minDiscount,omitempty"`
+
+	// MinTicketPrice Minimum price per ticket (including fee) in pounds (£) (Optional)
+	MinTicketPrice float64 `json:"minTicketPrice,omitempty"`
+
+	// MaxTicketPrice Maximum price per ticket (including fee) in pounds (£) (Optional)
+	MaxTicketPrice float64 `json:"maxTicketPrice,omitempty"`
+
+	// Notification Names of notifiers to use
+	Notification Notifications `json:"notification"`
+}
+
+// RoutingRules defines model for RoutingRules.
+type RoutingRules []RoutingRule
+
+// SlackConfig defines model for SlackConfig.
+type SlackConfig struct {
+	// WebhookUrl Slack incoming webhook URL. See https://api.slack.com/messaging/webhooks
+	WebhookUrl string `json:"webhookUrl"`
+}
+
+// TelegramChat A telegram chat to send to
+type TelegramChat struct {
+	// ChatId Chat ID or group chat ID
+	ChatId int `json:"chatId"`
+
+	// ThreadId ID of the forum topic (message_thread_id) to send to, for groups with topics (Optional).
+	// Default: The general topic.
+	ThreadId int `json:"threadId,omitempty"`
+}
+
 // TelegramConfig defines model for TelegramConfig.
 type TelegramConfig struct {
 	// Token Get from @BotFather on Telegram
 	Token string `json:"token"`
 
-	// ChatId Your chat ID or group chat ID
-	ChatId int `json:"chatId"`
+	// ChatId Your chat ID or group chat ID.
+	// Use chats instead to send to more than one chat, or to a forum topic.
+	ChatId int `json:"chatId,omitempty"`
+
+	// Chats Chats to send to, in addition to chatId (Optional)
+	Chats []TelegramChat `json:"chats,omitempty"`
+
+	// ParseMode Formatting used by messages (Optional).
+	// Custom templates must be written using this formatting.
+	// Default: html.
+	ParseMode TelegramParseMode `json:"parseMode,omitzero"`
+
+	// ApiUrl URL of the Telegram Bot API server, if using your own (Optional).
+	// Default: https://api.telegram.org.
+	ApiUrl string `json:"apiUrl,omitempty"`
+
+	// Commands Whether to handle commands sent to the bot from the chat, to manage the tickets being watched (Optional, Requires restart).
+	// See README.md for the supported commands.
+	Commands bool `json:"commands,omitempty"`
 }
 
+// TelegramParseMode Formatting used by telegram messages.
+// - html: Telegram HTML e.g. <b>bold</b>.
+// - markdownv2: Telegram MarkdownV2 e.g. *bold*.
+type TelegramParseMode enum.Member[string]
+
 // TicketListingConfig TicketListingConfig represents configuration for specific ticket listings
 // Configuration overrides global configuration
 // To reset a global configuration to its default, use:
@@ -159,6 +518,10 @@ type TicketListingConfig struct {
 	// Overrides global setting.
 	EventSimilarity *float64 `json:"eventSimilarity,omitempty"`
 
+	// Countries Countries to search for tickets
+	// Overrides global setting. To reset to default (all scanned countries), use an empty array [].
+	Countries Countries `json:"countries,omitzero"`
+
 	// Regions Geographic regions to search for tickets
 	// Overrides global setting. To reset to default (all regions), use an empty array [].
 	Regions Regions `json:"regions,omitzero"`
@@ -175,44 +538,134 @@ type TicketListingConfig struct {
 	// Overrides global setting. To reset to default (any price), use -1.
 	MaxTicketPriceInclFee *float64 `json:"maxTicketPrice,omitempty"`
 
-	// Notification Notification services to use
-	// Overrides global setting. To reset to default (all configured services), use an empty array [].
+	// Notification Names of notifiers to use
+	// Overrides global setting. To reset to default (all configured notifiers), use an empty array [].
 	Notification Notifications `json:"notification,omitzero"`
+
+	// RoutingRules Rules choosing the notifiers to use for listings with a discount or price
+	// Overrides global setting. To reset to default (no rules), use an empty array [].
+	RoutingRules RoutingRules `json:"routingRules,omitzero"`
+
+	// TelegramThreadId ID of the forum topic (message_thread_id) to send telegram notifications for these tickets to (Optional)
+	// e.g. to have a topic for each artist. Only used for the chat set by telegramChatId.
+	// Must be set with telegramChatId.
+	TelegramThreadId int `json:"telegramThreadId,omitempty"`
+
+	// TelegramChatId ID of the telegram supergroup chat the forum topic of telegramThreadId belongs to (Optional).
+	// Other chats are sent to using their own topic.
+	// Must be set with telegramThreadId.
+	TelegramChatId int `json:"telegramChatId,omitempty"`
+}
+
+// WebhookConfig defines model for WebhookConfig.
+type WebhookConfig struct {
+	// Url URL to POST notifications to
+	Url string `json:"url"`
+
+	// Headers Headers to add to requests (Optional)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Secret Secret used to sign requests (Optional).
+	// If set, requests have a X-Twitchets-Signature header containing sha256=<HMAC-SHA256 of the body in hex>.
+	Secret string `json:"secret,omitempty"`
+
+	// BodyTemplate Go template used to render the request body, instead of the JSON payload (Optional).
+	// The template is rendered with the JSON payload.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
-
-	"H4sIAAAAAAAC/8RY3XLbuhF+lS1OL+wZSrKb9JwJr+rIikdTW07tZDydyBcQuaLQgAAPANpWM3qavkmf",
-	"rLMAKVESZcmO215RBBf7//fpB0t0XmiFylkW/2A2mWHO/c++VlOR0a/C6AKNE+jPeSH+inP6laJNjCic",
-	"0IrF7Gbwt6/Dm8F5DLeIcDM4O78adPMUptpAio4LaUErmOlHcBr0xHGhWMTcvEAWM+uMUBmL2FMn0x3F",
-	"czo8+zwkUXSoTYqGxaeLiCW6VM54Df5ocMpi9ktvZUWvMqHXr8gWEZtKbtBq+YDGfDVyW/evN5egp/CJ",
-	"6G4DHRRGP83BonlA442YzAturVAZ9KUuU88Ujq49Dy6Pd9lChx37XRQdXZF2Ci2UI3OcKbFh3btFxDKp",
-	"J9yryKW8nrL42/NmXnj6LyL5ju5SWCdUVkVucb/uzSZlRdKQ/edFxJR2YioSHrzyvNxRg7YWGDHnufs8",
-	"EQ5zu49Lq95LR3Jj+HwjJ5r626YBvy4WETP4eykMpiz+VifqKmE2DFz6eqX2/VK0nvwDE0e69Ffptp40",
-	"1QdIdIrdseqXxqBycg5ayTlcfARhwZZFoY3DtDsmgajKnFS7+NgQtZ0uLGbuUWTa2W5/qfsqmUROPH1l",
-	"cjdjMcuEm5WTbqLzHp/pidXK8jka26u4MHLN7kTZsmwnKRgsDFoKIST+pDTemWDREZkFN+MOeFHIOZU5",
-	"lxKCc0EGRnasSiXRWsCnQopEeI9RxYk0RQWTOXCwBSYUqPrumqzuWJ2peS0RlHY1PabwKKSE0iK4GUKK",
-	"U15KF3y/3sVSYX1abBt/JZTIyxxqCjgSKpFlSrKmiMfUxIi5NiITiksojEgQuAUOBZoEleMZjtV5EB4D",
-	"KdvGTOnl8XFQcapNzh2LWarLicRVR1FlPkGzUQlXQp3XVry817xfRAwfULlbkQvJjXAtKT4gAiB5YJdk",
-	"kHOXzMiCo5PuCXTgtHtCBiwNPul+gCMupX60vnHmQmlDXOhOKqZTNKgStMcvMfoFptGQyPlTyN/PFJ2W",
-	"KPMnH+UQvAJNnWqb0RYKCl2q1MLRv/91vBFWf/tVsVtTb6gS+QnxFaa2de11Q5t92k8zkaCl0iztWpJK",
-	"uawyTJeEwbaDWnlT0BcyfUcff4F1v5J1Zf5lNVTaSzW4mCZ41cihngMUvqrxbIRu604wtdKY9MlelXzv",
-	"/BTKhFYtCl+gzgwvZiKBioYiYZGbZOZLZaXLWmRqYjFdb3fdsfpUSulNjGHmXGHjXm/fOOhNpJ70ci5U",
-	"T+oQsG6mf7n87UPn8sPJ4RG/8Vq9QZz/tFi0DN4LSqj5rjXU6e/Yku5nBQ2VkO2eBKZG5xB4ta5oSx0i",
-	"Vrbthn/Xpanu19vg15vL51idbm4ixDeqNG5bMVp2qYPqeH0utky5LJi9J45rjqaSc/vvjFzzhkOJmeH5",
-	"3nWvoqtvLvY4w/eR+Mdyb/KaRbVZDblb29QGo8M30r1tjDi73XlJGOFRm3Q7gp+rL77QeelmqBwJ8l3K",
-	"Ok4j8W3xBM14pwuRbCsznELpsUzdNMi1XTuLIOffEWxpaIUSlpbYUonfS6RONdflH15bRJBwtdzMinIi",
-	"RbK0G7jbVOT5AotYadGEYboF5qov/zs/v9tV8OT6toKvOuc2hvbnAVHAWH3W1oqJRHjgskQL3GA8Vh24",
-	"+Hh5HcOlVilVPb3fXsdwq0s3q17vqle4Q+uqs0F9NuD12dUwhiuRSq5SG04GZ7H/Dmcqk4KHw9F1DCNt",
-	"au6jQfXa4DS6q88aEvsx3CbaEftwcncWwx2XWAkbDatLaBQMDQbCNZx0ec0iRvaFx114DPzjaugfgzP/",
-	"GAWSUfg2qij7/nFXkQwPhV1VgH4edd2sVoGfmamLiG30zq3Gk8y4G6Y75hd9hOE5aAOZ0WVRH7RvPI1q",
-	"3jFmL9CFwfqXj9p94hRBwkW1ji+ajkFEVBvQVi8HodYX4VXqDZsoc4VQ+2u0FTpFC+EPg82x+0UDCXHA",
-	"WwmocQpnazQaURP0ZTxmYwZHmBduDsFPx0Ev/7sqeiL8dl+T+WwIVKRtg6Zz6k9VmaMRyfLDzwDfA4Hu",
-	"9aZ7KmTehaVjnK6thyPegMPH3hnQOX0L/NuARB7YPgdn902Xt0XGOz10sNWNivyvIdtXxNHL+Lkg7gTC",
-	"TQy4gXAP+2d0ff9b3EcvQsYv9UY7fq58wxU06he+3QdXHb5n5DQ8CkrEKZd2efZPNHprG/ltD2oeHYaW",
-	"X5EOIcIb+bB7vrxfh8qHRbUeqNvxPBRcvyq2Fcf/Rzy3tsvQ3bbnJNEJNdUeHgsn/d/lj8IlMwry+ky7",
-	"0ilKyyL2gMYG/512T7onNG11gYoXgsXsXfek+55GCHczitFi8Z8AAAD//7i9wPYxGgAA",
+	"H4sIAAAAAAAC/+w923LbOJa/guXsQzxFy5fEuahqq8axncSztuO2nc1utbumIPJIxJgEFAC0ou7y1+yf",
+	"7JdtnQOAIiVSkh25e6ZqnhyRuJ0rzpX5LUpUMVYSpDVR/7fIJBkUnP55pORQjPBfY63GoK0Aes7H4j9h",
+	"iv9KwSRajK1QMupHVyc/fTm9Ojnus2sAdnVyeHx+0itSNlSapWC5yA1TkmVqwqxiamC5kFEc2ekYon5k",
+	"rBZyFMXR9+2R2pa8wIeHl6e4FT5UOgUd9fce4ihRpbTan6Z5hsM0FfhPnrNqFO5mEi7pIFYkd2BNzHiu",
+	"5MiIFPzAKXvxeeymbvVu8WDCQkFb/LuGYdSP/rQzQ9WOx9POkZscPVSAcK35NMCBz7bNnRhvK7/49lgJ",
+	"aREUq0uoQfaygmz6iE2HOddgVH4PWn/R+SJGvlydMTVkH3DctRvHxlp9nzID+h40YWUwHXNjhByxo1yV",
+	"KS1aw0cXlR4B3ZuHOBrlasDpiDzPPw+j/s/LwfxI42+IYmfCWCFHnicffmnySX2kH1Lnmf2HOJLKiqFI",
+	"uEPLuke4qM2q7d1EcX0QYVUkYGKmJCDmgScZQ0w1GewEH/vBTBjGmTshaIZApYwPLWgmrHGToTfqMWmH",
+	"096t/GKgGk3sndDRSg2sUBqYzbik7cP6ashsBszwAmg1x+Dr07BAWRjbadQf8txUz34FrRYovbdbYRt0",
+	"i4heEHSyDWdNDOFAw4rSWDYAVkrxrYSYCZnkZYqsihBJGqOGHetVeEmZkI0xjxDxCw9LoH+7pHtObA42",
+	"T5CUPVRxY14aSBeR9zUDmyGL1EAxDKXVzWhi0AmEe2+syHPSgxKcTp5kIg/zYjYoLZOqZWED0tJ4m0Hh",
+	"0ObhHyiVA5dPgPHdQxzhUU7x7T1vUVuf1ISpoQXZ0N4SJl6Ds9wpBOME4+WuidkeHu/c8wu3LAduLDsw",
+	"PXaUcTkCwyy/AwbDISSWTYTNVGkZZxqM5dr2buUxDHmZ2z7ba0K6qPaifnRcaqdNHg//Kw//X4Wl1/PQ",
+	"n/PvoigLprlMVcGsKIDxNIUUsUEKhVAiPPpifMzvlUjpuUTp4JZpGJU51zTd42lv19TBvFDs73SEZ4X2",
+	"wEN7zr+/58mdGg67ISZQrWITLpCMdgIgCSjDJhnIGXx3AGPDhlzkQo6Q1zNwk5uTUlUOcjBemxLqEiUN",
+	"JKUV90DzSw0xK8e4q82EYYU7SoMdDp6XH14jhiy3cMlttogcfBp0+BCFlkQdBcMqEm1uSelbpy1Nqe8R",
+	"OM/XJr6Vpkwyxs1Mamh0xu+B8VwDT6dsgEjzerumR2J2Bd9KocGE9bbqmKG9e+mACUnHmyh9h+RJhYbE",
+	"Kj1dgbc1EfQWta7TZoietfR2q+GwVHnXDQjTsCBePjzEkXaISKP+z8EInplslXkzO+gv1WZq8HdILO5+",
+	"VLddf8jCrBZrMcX9C5aoFO/6o1JrkDafMiXzKfv4ngnDTDkeK20hdRQCWRYI2Mf30S9L6BX1IzsRI2VN",
+	"76iCfEZNUeCa5DMQJ0cjYbNy0EtUscMzNTBKGj4FbXb8KhEi9liYROm0y+EoDWhHoTZbguQAZMoKMIaj",
+	"lucmZkIaCzwNUjOBQabUHdkLmzVs0bb0q7fa3x626gRfrs567EgDiqySEOQGFx9pf+0asE5K1ZBxlmR4",
+	"Z+etZ628ojn2rJ2ojQtPCi7yLnQPtSpaPSsNxlTYhoIcOhq85GSo2TJl7OKC1+c3l8EHoRFL4UOTyJiJ",
+	"0mmbenRvyETgpc1AWjRhIGW1Tcxm6Y6XWuD1bsBwhLt5D96+ofNd3xxe3dycXcfs1esDenJzdj07D/EB",
+	"6GiOw/DC0sJO1/dciMTXYdqi0/LJueG0MBALJkpKSPC1uwqhjr2mYVlX/9ra3DynQ4GOsVWdHAkLPGlV",
+	"3bRvErrtAqi5qN2q5ot/87sy2at5ufaCQowXR0H4VLeQX9cY53EMwGUdsN6t3GZSSeizY/IS2if22Ge8",
+	"YUoDzpZCZAXMKMmmqtRMTSSTYNFS6N1Kxg5ZwDrjMmVBzllCTmw+ZQNv8KDF7pYVTqsjNpgwLFcJz/EH",
+	"nTLwZJ99GY80T9uAuzm7ZiUFPII80lSadeRG+vc48oUoxrlIhMVfW837EnESxVHYFMmRm8UrFOnxHZLO",
+	"mJoetUWz9KgsUKrxyLqUHpCi4NJjo8Ftj2D5x99xfts2W8Odxx+RlN2OG2B2yJ6c9kzmLfRweGFosLMS",
+	"82lMHCUk48xkkOddRmPtOkBbX5V2lSdR2xP5CTelgBcMlQYmiH2MVeMxpJWH1NR1R1zi4QbAMGII2kVX",
+	"rHc30IP0NkaIedT148td86yOw4JdGsjUphC642kLSOwcyjSMNRhiyhBdCUEXW3Mt+HicT0mN5Pm8y34r",
+	"S5mDMQy+O7FCwxQDkyJNQbLBFNlgDAkGIsLcxl69W3kop2FHp43ceNISee4VELDUEcLRoClyS6LIR43Q",
+	"MXCdZPXgMTJFNSLEp3xkJWaiBz18AIIiNSG6rHQICFYb1xnlsBacqQagnmsA94cEplH6U2HoVC3iJiSJ",
+	"WxjBXswidEOALaacsCgtRgLj8mMtEmDcMM7GoBOQlo+gjgk5bV1MquqxV8FDpQtuo37knPyZlMmyGICe",
+	"c+7OhTwOUDzN3IN7kPZaFCLn7VfqCQ5wPoaphrGC2yRDCF7s9nbZNtvr7TZsqN3eO/aC57mauPuyEFJR",
+	"ENj50cMhaJAJmK3HAP2YgONDHBX8uxP2S6ROt1J1xBtDkIUFagvJxqqUqWEv/u9/t+bISrOfRLvG8U5l",
+	"kn8AeGKcZT4RsOhR1gLKPr5eGpiT1VpkuRo5J52bvoXRPpVlcTMLg7RLosMg3UpuKAsXBFLHK+E5yizM",
+	"adxadafk8YFODSOhZMuBP4IaaT7ORML8mE59e+XfB20rZNCm7oYPCnMAyIluCVKXDaKFXVrU6ocyzwk3",
+	"fZZZOzb9nZ1VoYudQa4GOwUXcgcNULqYRupPZ2/ebZ+9211fUzvYNpRB1KpE8l6VOZj1ncWr+qxFX5Ge",
+	"syRTylSJlznxIHpV0UWyTflMjStdyb5bi2tgSQbJnWNKAiAm87+5eBX01MYyXebuVvf7OM3qV0MPoXcr",
+	"T4eNEakCQ7SmoYzLKa0SN/NFwoTp9cg4Dny0YxvClQ9t9hcZw10uwFgL1X6tXPo383kuE1P4he2yF4WQ",
+	"W0iJPfw3/77V4a4fdIp1jYXCQSoeaj2NWMy7mfWYYC5HtZq8njwxU2hRTYQBFs5YI9264nZZA28DQoca",
+	"zqo7aLlLDsfj3KOG0RBHLscGHnT3XJhKGijLYScCQbeG+Qg+c/k3f+3geA254mmAvNNVQuOtbItL/g+6",
+	"4u4oIcTz5ersUUFGXDcA3+ZvnH+ztovbB1rdtWa+frq5Ye4lnsf5ZDYZ93d2Ki+/v/f27UufBjcm7+/s",
+	"hDga4WmCypt0ycSY8HICA6MWb7Z23zLJBUh72ubs0ht2euyz7jJkEjsEriLkZtIgL4Mpfg96eqlhKL63",
+	"BXYKYIfGCGO5tIwwWk1iY5rVcdxMFcDDzM0c+R0FgAuoDnQcjtKd3baKjctBLkzGlgPjxMHMaxQUJ4qw",
+	"G8bZbZRz4y+A24gZkEa5qhfKBpJRv6G09tunhqkdwz9DhPqbatHgP5U899dJKBCpYRy5GcN4ZF3txmwP",
+	"ZWq/g2F2N2QnvibVQhVh3VyRQdAMJlNlnjI3I5wc0urGaMPj08n6hlT8WCSLZ7vBx3XshRNQDHq5Vn5K",
+	"qPlZGGUhyux2iQLUbbq9pTBq0ZdqqclpBnJawjIjupJWXeINUwr9Irt6zoWtz7CQY8KtWJlF9uPCzIcV",
+	"yLihl7/N4sN4sjiAVdu3ShtGXqfrNIojk/MEn1AqI4qj4pu1+PM7JIiq0mSo+Vrjy/VTNNPLyz3Rh3iu",
+	"aqnFkvE1aW1VVljL5kJduGplw9dzmPgbX9L1XOo8yO+AhrUF5wYoRl+FTNWkRejqNSKusINXRiPJjHRq",
+	"nVEGopRpiPe6cLSQo9gZFvtFU6+dBdvV1zu5ijm3llvIFU3hzrgZuTGqGAhJfoxV5JWmYgSUi/jqCBwz",
+	"pCJhBulYc29c1gqvoXB8JankD3MkPoXd9EqcvWY1F6PMMj7h02eNL++9I4sD4bmBYpxz26KvPipm/cuq",
+	"MkWDTEF7VJgY8ZZkAVc1Jy94DnQX3YPm+cyB6AjD0xaDKZuswG7YdBmS68h9X4qckhABmM0YQHtvHmYC",
+	"vkLZNAshHoIa6K+R6q3NQV2xasosF/UQP1HnkmpaMafmAqCWbr/uqLLThU3nUilxxU8UfABP0dbgQ6i6",
+	"WWHbP/6mqJTuKsfSj5vN/FYKsJ9UqVc6pT9VI2ez3VWwYuI1DvrxWy2OAtM/Srwb90FVf7NMbl0O1y/3",
+	"QeTwe0jgqxp8uGd3hR139XWJkpYLV0fJng/+DYF3sH461F+adEk2T2+Vu46l9XfjXtFh8z93TnN/l+Cx",
+	"Odz8ME/SMvN15cpSVrI5cDoG4woi3Zw6xLO8zoYo9rqywZ7SiEAm5mKUFh83FeiCodbGjKXOn7N6pzMA",
+	"5RtTuk7bLOELlqWwteo4YtOgnfs7O+hR/YXCUV1yh60PLd1JeIaqHBLDThsM1wQjf4VC9qZizcuoO2R0",
+	"cba6YbY7oJxCzqfd1jO9nrPCBtNQTj8n/AtV9XvPrASoi0QkSnYyDpcM3yMoBnvKiMJNcDbqJb97anAH",
+	"+ZjLBDbutcc/kDPYm+UMDpamDF5uMMLzr8zCo8NOvK0u7IaPTAthvY/FJUoqFOrvwutIy1P+O5WKvV2S",
+	"DkkSV0BMmZAlYuLOfPe3Xq/XpCgG/Gu3QmvZ4GY090F3yO906CsDQ56YmvJMFrMCu4uMK4oUjrmca2MV",
+	"Vj7+21PzNETSUNFEgcakwhZq5LmDrPKAnhR4fCYl9rIrp9QVd2zI2CKLzcSXbv2G1qjqcKgkzSXTXLdw",
+	"FaRCRUHVXdULV8zmQkfMAFqOEz71i7VFrp6pkqaJ9s0XAeFtUtQqpFbXea1V1/Xcx953x16OcX/yfzCM",
+	"v1zv/nYRjqVJ+4XEbLVsqwQ1wxQtZuN9KxqPIWSKKKDWvJ6t6jAfsOxGDV3ht1t5g7Z110VzeepvmbBz",
+	"gJnKUv2h18nPdKTN8RVTmo20KsfsDqYxmaCyKnGv9ku5yQaK63TZbouJdeOzL12Z9YVwUUsSuNS+ZXKx",
+	"pTcDTJ5xn1Xj0vX4Ngj4CYd0NANzUwtzuz0o0sUy2hRk2hrSp28MbFzBNFT8LIjB0hLR3DhYN4dWCwTs",
+	"9G7ls6lZkGmHVzaHxZgJyfZf0QP26VP//Jy5EzkLafdNf3d3FRdT/cga29G4FRvu76/YMNTl/6oktO/5",
+	"a61qj7Z0cXuZ1puUT0pknJ0zJVMlO6h2hsUgLGy2KZUyJ4cOeY5ibWLoK/cWv4RCz133JbuVl8oYMciB",
+	"3fO8dOVqfew2+fj+7HOfOTDd7+vPfXatSpv5n1/9T/YVjPXPTsKzEx6enZ/22blIcy5T456cHPbpPTuU",
+	"o1xw9/DiM2aQdFj94sT/rK108TU8q+141GfXibK4vHvy9bDPvvIc/GYXp34SaMlONbiBjZ7Ss89RHCF8",
+	"7s9X9+eE/pyf0p+TQ/pz4YZcuHcXfuQR/fnqh5yu26LqCfTjHapXsxrWH6npxJVmpZZt9iu5qo+otPyX",
+	"UbvCRvgnNGr3/nmN2s18aaetBrmzMD9aVsnSOEurCp+rl15PumeT2kS8npNbMK+X9YrTRCZkoqj3o9Ey",
+	"jlHr4OjzsehRdpC0lss7CTna8RPMJlvFq0xhxm2bwgoZR2xTt5V3QOVPTcDxfWtJJc47PZ7Z04l7sMLl",
+	"iSObaeBp25Knx0H3DZXG3BdVab1wiIK/uYl/E+lW7bwxG4YT+AAjzeqyGjG4OAJJBQs0cENh0v158ni0",
+	"LSVN94favqzIvIQl2HtlGfpNrhQ4ZiJEuaqW3Y6K0RpLBlboKT3aXBiui2/IE0s6mMcHC/GXqUKGM2LP",
+	"fSYLh7kSa8V4nWc2RlQHhmnnftNgQyHxUz8iJGUd+B3R27VS/Ci4m/o8HTWXmqWVuxmXaV513RrnjfkP",
+	"CgyUdekH/OGwjsTgEm9Vm4G/xEIfz4TC9Cs/RrMqoReOsqEqX8pkcG3gXKWPyOAGelxWUxcvuQ90IVOK",
+	"IlQ5dVQVlMaqosqBzxqjJlpYC9ILb+jB94s2Bbd41rzvkr6Ij+C54C/vlf3gwhBKVrroUbdXd4xkEd/9",
+	"31aju7rOAt7puwCIrP5MWX66OfedCbfl7u7LZEB/YKDy1D3Y8U9ocsH1Xaom8n6/tsS5f/hf+26hP+Pk",
+	"Pzc/LYC7RtibGea3ln2u1c/9qE5ulKD5/utZ7/ZRY6zv2wbD3OeP5ut7bxRKKljGWweg+AtrQp821XuR",
+	"b3wb3UbsBXEbc7BuuXPRv70njQN//iUMI822VblHtTHbe/RUlgVokVQvlreEryfVs+85LUrzijbyW/l5",
+	"Hnm+tKHHKrRZFXBDPcKLPeJbhDMqeZ1hgf38y3N/kSXdsFP1eGzU+sQ9Erb3NtEYXlPzVPm7rM97VTRs",
+	"sy3jnRhaG+p62uS5Wr6fQEfa48eI2NkhXu+e/n090ycJeFtj+R8h429XdJtfrNdl/gRmcPSd44ZuV/Cg",
+	"2WK+ZsMzjDqouW5T+pOI61f8I+j56h++OfzRGJW+R/uPQOe7WrH1UYeHOgtDhJHolICuearzMQo1rMbe",
+	"+PAGGwB9qnwhx/rZfV+GHMgqN0cI93QQznEPnmyo4UM0+jLk5k4b8nbpA8bza28kSBOwuFgaZjMwM++x",
+	"galb6eqJlP/cqd+oagDl2gpjZx8um3mQRCHEVs0vcLRehs1qxEZwuTvv8DiTpM3haZaQLnZcq3T6pIJq",
+	"RAWeABBglU4XPq751+vPF2zMp9iQvliCVy0rjF9x9im35tzNxIxQ0WXAU//xcV79jwCXDXQs+FFz+XO3",
+	"AkWEUo8NQkF7AZSnwtNCQwYSDW0fkqTnFTmMGMm2U7ivXhiw8eytZ/X/3r4JDejb12IkuS0pwY2w1Zsc",
+	"TMb3D17/h3NeP50fHm1ffzrcP3gdKIxkZ0KyDL5Xju2Gajg6a8OtYpefr28WCk0e/YmCRVnBUUIO6ZuS",
+	"VO2PHnJAFGs6uBg5oM/63YM27nR7vd3eLrKMGoPkYxH1o5e93d4r9Ce5zZC9Hh7+fwB3ZlKBWWMAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -3,11 +3,19 @@ package config
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ahobsonsayers/twigots"
+	"github.com/samber/lo"
 )
 
-func (r Regions) IsZero() bool { return r == nil }
+func (c Countries) IsZero() bool { return c == nil }
+func (r Regions) IsZero() bool   { return r == nil }
+
+// Minimum allowed scan interval, to avoid being rate limited or blocked
+const minScanInterval = 5 * time.Second
 
 func (c Config) Validate() error {
 	if c.APIKey == "" {
@@ -17,8 +25,23 @@ func (c Config) Validate() error {
 	if c.Country.Value == "" {
 		return errors.New("country must be set")
 	}
-	if !twigots.Countries.Contains(c.Country) {
-		return fmt.Errorf("country '%s' is not valid", c.Country)
+	for _, country := range c.ScanCountries() {
+		if !twigots.Countries.Contains(country) {
+			return fmt.Errorf("country '%s' is not valid", country)
+		}
+	}
+
+	if c.ScanInterval != 0 && time.Duration(c.ScanInterval) < minScanInterval {
+		return fmt.Errorf("scan interval must be at least %s", minScanInterval)
+	}
+	if c.ScanJitter < 0 {
+		return errors.New("scan jitter cannot be negative")
+	}
+	if c.ScanMaxBackoff < 0 {
+		return errors.New("scan max backoff cannot be negative")
+	}
+	if c.ScanInterval != 0 && c.ScanMaxBackoff != 0 && c.ScanMaxBackoff < c.ScanInterval {
+		return errors.New("scan max backoff cannot be less than scan interval")
 	}
 
 	err := c.Notification.Validate()
@@ -26,9 +49,174 @@ func (c Config) Validate() error {
 		return fmt.Errorf("notification config is not valid: %w", err)
 	}
 
+	notifierNames := make(map[string]struct{}, len(c.NotifierConfigs))
+	for _, notifier := range c.Notifiers() {
+		err := notifier.Validate()
+		if err != nil {
+			return fmt.Errorf("notifier '%s' is not valid: %w", notifier.Name, err)
+		}
+
+		if _, ok := notifierNames[notifier.Name]; ok {
+			return fmt.Errorf("notifier name '%s' is used more than once", notifier.Name)
+		}
+		notifierNames[notifier.Name] = struct{}{}
+	}
+
+	err = validateNotifierNames(notifierNames, c.GlobalTicketConfig.Notification)
+	if err != nil {
+		return fmt.Errorf("global config is not valid: %w", err)
+	}
+
+	err = validateRoutingRules(notifierNames, c.GlobalTicketConfig.RoutingRules)
+	if err != nil {
+		return fmt.Errorf("global config is not valid: %w", err)
+	}
+
+	err = validateCountriesAndRegions(c.ScanCountries(), c.GlobalTicketConfig.Countries, c.GlobalTicketConfig.Regions)
+	if err != nil {
+		return fmt.Errorf("global config is not valid: %w", err)
+	}
+
+	for _, ticketConfig := range c.CombinedTicketListingConfigs() {
+		err := validateCountriesAndRegions(c.ScanCountries(), ticketConfig.Countries, ticketConfig.Regions)
+		if err != nil {
+			return fmt.Errorf("ticket config for event '%s' is not valid: %w", ticketConfig.Event, err)
+		}
+
+		err = validateNotifierNames(notifierNames, ticketConfig.Notification)
+		if err != nil {
+			return fmt.Errorf("ticket config for event '%s' is not valid: %w", ticketConfig.Event, err)
+		}
+
+		err = validateRoutingRules(notifierNames, ticketConfig.RoutingRules)
+		if err != nil {
+			return fmt.Errorf("ticket config for event '%s' is not valid: %w", ticketConfig.Event, err)
+		}
+
+		err = validateTelegramThread(ticketConfig.TelegramThreadId, ticketConfig.TelegramChatId)
+		if err != nil {
+			return fmt.Errorf("ticket config for event '%s' is not valid: %w", ticketConfig.Event, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTelegramThread checks a telegram thread override has the supergroup chat it belongs to
+func validateTelegramThread(threadId, chatId int) error {
+	if threadId < 0 {
+		return errors.New("telegram thread id cannot be negative")
+	}
+	if (threadId == 0) != (chatId == 0) {
+		return errors.New("telegram thread id and telegram chat id must be set together")
+	}
+	if chatId != 0 && !(TelegramChat{ChatId: chatId}).IsSupergroup() {
+		return errors.New("telegram chat id must be a supergroup")
+	}
 	return nil
 }
 
+// ScanCountries returns the unique countries to scan for tickets.
+// This is country, followed by any additional countries.
+func (c Config) ScanCountries() []Country {
+	countries := make([]Country, 0, 1+len(c.Countries))
+	countries = append(countries, c.Country)
+	countries = append(countries, c.Countries...)
+	return lo.Uniq(countries)
+}
+
+// Notifiers returns all notifiers. This is the notification services configured
+// in notification (named after their type), followed by the named notifiers.
+func (c Config) Notifiers() []NotifierConfig {
+	notifiers := c.Notification.Notifiers()
+	notifiers = append(notifiers, c.NotifierConfigs...)
+	return notifiers
+}
+
+// TelegramCommandConfigs returns the telegram settings to handle commands with, one for each bot.
+// Only one handler can poll a bot for commands, so the chats of all notifiers
+// with commands enabled that use the same bot are combined.
+func (c Config) TelegramCommandConfigs() ([]TelegramConfig, error) {
+	var telegramConfigs []TelegramConfig
+	for _, notifier := range c.Notifiers() {
+		notifier, err := notifier.ExpandUrl()
+		if err != nil {
+			return nil, err
+		}
+
+		if notifier.Telegram == nil || !notifier.Telegram.Commands {
+			continue
+		}
+
+		idx := slices.IndexFunc(telegramConfigs, func(telegramConfig TelegramConfig) bool {
+			return telegramConfig.Token == notifier.Telegram.Token
+		})
+		if idx == -1 {
+			telegramConfig := *notifier.Telegram
+			telegramConfig.ChatId = 0
+			telegramConfig.Chats = nil
+			telegramConfigs = append(telegramConfigs, telegramConfig)
+			idx = len(telegramConfigs) - 1
+		}
+		telegramConfigs[idx].Chats = append(telegramConfigs[idx].Chats, notifier.Telegram.AllChats()...)
+	}
+
+	return telegramConfigs, nil
+}
+
+// NotifierNames returns the names of all notifiers
+func (c Config) NotifierNames() []string {
+	return lo.Map(c.Notifiers(), func(notifier NotifierConfig, _ int) string { return notifier.Name })
+}
+
+// CombinedTicketListingConfigs returns the ticket listing configs combined with the global config.
+// If no notifiers are set globally, all notifiers are used by default.
+// A ticket listing config with an empty list of notifiers also uses all notifiers.
 func (c Config) CombinedTicketListingConfigs() []TicketListingConfig {
-	return CombineGlobalAndTicketListingConfigs(c.GlobalTicketConfig, c.TicketConfigs...)
+	globalConfig := c.GlobalTicketConfig
+	if len(globalConfig.Notification) == 0 {
+		globalConfig.Notification = c.NotifierNames()
+	}
+	return CombineGlobalAndTicketListingConfigs(globalConfig, c.TicketConfigs...)
+}
+
+// validateNotifierNames checks that notifier names refer to configured notifiers
+func validateNotifierNames(notifierNames map[string]struct{}, names []string) error {
+	for _, name := range names {
+		if _, ok := notifierNames[name]; !ok {
+			return fmt.Errorf("notifier '%s' does not exist", name)
+		}
+	}
+	return nil
+}
+
+// validateCountriesAndRegions checks that countries are being scanned, and that each region
+// belongs to one of the countries. If no countries are given, all scanned countries are used.
+func validateCountriesAndRegions(scanCountries, countries []Country, regions []Region) error {
+	for _, country := range countries {
+		if !lo.Contains(scanCountries, country) {
+			return fmt.Errorf("country '%s' is not being scanned", country.Value)
+		}
+	}
+
+	if len(countries) == 0 {
+		countries = scanCountries
+	}
+
+	for _, region := range regions {
+		inCountry := lo.ContainsBy(countries, func(country Country) bool {
+			return RegionInCountry(region, country)
+		})
+		if !inCountry {
+			return fmt.Errorf("region '%s' is not in any of the countries being searched", region.Value)
+		}
+	}
+
+	return nil
+}
+
+// RegionInCountry returns whether a region belongs to a country.
+// Region codes are prefixed with the code of the country they are in.
+func RegionInCountry(region Region, country Country) bool {
+	return country.Value != "" && strings.HasPrefix(region.Value, country.Value)
 }
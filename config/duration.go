@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that is marshalled to and from a duration string e.g. 1m30s
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(data []byte) error {
+	durationString := string(data)
+	duration, err := time.ParseDuration(durationString)
+	if err != nil {
+		return fmt.Errorf("duration '%s' is not valid: %w", durationString, err)
+	}
+
+	*d = Duration(duration)
+	return nil
+}
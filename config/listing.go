@@ -25,6 +25,13 @@ func CombineGlobalAndTicketListingConfigs(
 			combinedConfig.EventSimilarity = config.EventSimilarity
 		}
 
+		// Set countries, using global if not specified
+		if config.Countries == nil {
+			combinedConfig.Countries = globalConfig.Countries
+		} else {
+			combinedConfig.Countries = config.Countries
+		}
+
 		// Set regions, using global if not specified
 		if config.Regions == nil {
 			combinedConfig.Regions = globalConfig.Regions
@@ -53,17 +60,24 @@ func CombineGlobalAndTicketListingConfigs(
 			combinedConfig.MaxTicketPriceInclFee = config.MaxTicketPriceInclFee
 		}
 
-		// Set notifications, using global if not specified
-		// Default to all notification types if both are empty
+		// Set notifiers, using global if not specified
 		if config.Notification == nil {
 			combinedConfig.Notification = globalConfig.Notification
-			if len(combinedConfig.Notification) == 0 {
-				combinedConfig.Notification = NotificationTypes.Members()
-			}
 		} else {
 			combinedConfig.Notification = config.Notification
 		}
 
+		// Set routing rules, using global if not specified
+		if config.RoutingRules == nil {
+			combinedConfig.RoutingRules = globalConfig.RoutingRules
+		} else {
+			combinedConfig.RoutingRules = config.RoutingRules
+		}
+
+		// Set telegram thread. There is no global setting
+		combinedConfig.TelegramThreadId = config.TelegramThreadId
+		combinedConfig.TelegramChatId = config.TelegramChatId
+
 		combinedConfigs = append(combinedConfigs, combinedConfig)
 	}
 
@@ -4,9 +4,36 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 )
 
+// Range of ntfy priorities
+// See https://docs.ntfy.sh/publish/#message-priority
+const (
+	ntfyMinPriority = 1
+	ntfyMaxPriority = 5
+)
+
+// Range of gotify priorities
+// See https://github.com/gotify/android#message-priorities
+const (
+	gotifyMinPriority = 0
+	gotifyMaxPriority = 10
+)
+
+// Minimum time ntfy notifications can be delayed by
+// See https://docs.ntfy.sh/publish/#scheduled-delivery
+const ntfyMinDelay = 10 * time.Second
+
+// DefaultNotifierTimeout is the maximum time to wait for a notification to be sent,
+// if the notifier does not set its own timeout
+const DefaultNotifierTimeout = 30 * time.Second
+
 func (c NotificationType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.Value)
 }
@@ -38,37 +65,500 @@ func (c *NotificationType) UnmarshalText(data []byte) error {
 	return nil
 }
 
+// SendsDigests checks whether notifiers of the type can combine the notifications of several listings into one digest.
+// Webhook, mqtt and exec notifiers send a payload for a single listing, which other programs rely on,
+// so always send each listing on its own.
+func (c NotificationType) SendsDigests() bool {
+	switch c {
+	case NotificationTypeNtfy, NotificationTypeGotify, NotificationTypeTelegram, NotificationTypePushover,
+		NotificationTypeDiscord, NotificationTypeSlack, NotificationTypeEmail:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c EmailSecurity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+func (c *EmailSecurity) UnmarshalJSON(data []byte) error {
+	var emailSecurityString string
+	err := json.Unmarshal(data, &emailSecurityString)
+	if err != nil {
+		return err
+	}
+
+	emailSecurity := EmailSecuritys.Parse(emailSecurityString)
+	if emailSecurity == nil {
+		return fmt.Errorf("email security '%s' is not valid", emailSecurityString)
+	}
+
+	*c = *emailSecurity
+	return nil
+}
+
+func (c *EmailSecurity) UnmarshalText(data []byte) error {
+	emailSecurityString := string(data)
+	emailSecurity := EmailSecuritys.Parse(emailSecurityString)
+	if emailSecurity == nil {
+		return fmt.Errorf("email security '%s' is not valid", emailSecurityString)
+	}
+
+	*c = *emailSecurity
+	return nil
+}
+
+func (c TelegramParseMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+func (c *TelegramParseMode) UnmarshalJSON(data []byte) error {
+	var parseModeString string
+	err := json.Unmarshal(data, &parseModeString)
+	if err != nil {
+		return err
+	}
+
+	parseMode := TelegramParseModes.Parse(parseModeString)
+	if parseMode == nil {
+		return fmt.Errorf("telegram parse mode '%s' is not valid", parseModeString)
+	}
+
+	*c = *parseMode
+	return nil
+}
+
+func (c *TelegramParseMode) UnmarshalText(data []byte) error {
+	parseModeString := string(data)
+	parseMode := TelegramParseModes.Parse(parseModeString)
+	if parseMode == nil {
+		return fmt.Errorf("telegram parse mode '%s' is not valid", parseModeString)
+	}
+
+	*c = *parseMode
+	return nil
+}
+
 func (c NotificationConfig) Validate() error {
 	if c.Ntfy != nil {
-		if !beginsWithHttp(c.Ntfy.Url) {
-			return errors.New("ntfy url must begin with 'http://' or 'https://'")
-		}
-		if c.Ntfy.Topic == "" {
-			return errors.New("ntfy topic must be set")
+		err := c.Ntfy.Validate()
+		if err != nil {
+			return err
 		}
 	}
 
 	if c.Gotify != nil {
-		if !beginsWithHttp(c.Gotify.Url) {
-			return errors.New("gotify url must begin with 'http://' or 'https://'")
+		err := c.Gotify.Validate()
+		if err != nil {
+			return err
 		}
-		if c.Gotify.Token == "" {
-			return errors.New("gotify token cannot be empty")
+	}
+
+	if c.Telegram != nil {
+		err := c.Telegram.Validate()
+		if err != nil {
+			return err
 		}
 	}
 
+	return nil
+}
+
+// Notifiers gets the configured notification services as notifiers named after their type
+func (c NotificationConfig) Notifiers() []NotifierConfig {
+	var notifiers []NotifierConfig
+	if c.Ntfy != nil {
+		notifiers = append(notifiers, NotifierConfig{
+			Name: NotificationTypeNtfy.Value,
+			Type: NotificationTypeNtfy,
+			Ntfy: c.Ntfy,
+		})
+	}
+	if c.Gotify != nil {
+		notifiers = append(notifiers, NotifierConfig{
+			Name:   NotificationTypeGotify.Value,
+			Type:   NotificationTypeGotify,
+			Gotify: c.Gotify,
+		})
+	}
 	if c.Telegram != nil {
-		if c.Telegram.ChatId == 0 {
+		notifiers = append(notifiers, NotifierConfig{
+			Name:     NotificationTypeTelegram.Value,
+			Type:     NotificationTypeTelegram,
+			Telegram: c.Telegram,
+		})
+	}
+	return notifiers
+}
+
+func (c NotifierConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("name must be set")
+	}
+
+	err := c.validateTemplates()
+	if err != nil {
+		return err
+	}
+
+	if c.QuietHours != nil {
+		err := c.QuietHours.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.BatchWindow < 0 {
+		return errors.New("batch window cannot be negative")
+	}
+
+	if c.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	if c.Url != "" {
+		if c.Type != (NotificationType{}) {
+			return errors.New("url and type cannot both be set")
+		}
+
+		expanded, err := c.ExpandUrl()
+		if err != nil {
+			return err
+		}
+		return expanded.Validate()
+	}
+
+	// A digest template would never be used
+	if c.Type != (NotificationType{}) && !c.Type.SendsDigests() && c.DigestTemplate != "" {
+		return fmt.Errorf("digest template cannot be used by %s notifiers, as they do not send digests", c.Type.Value)
+	}
+
+	switch c.Type {
+	case NotificationTypeNtfy:
+		if c.Ntfy == nil {
+			return errors.New("ntfy settings must be set")
+		}
+		return c.Ntfy.Validate()
+
+	case NotificationTypeGotify:
+		if c.Gotify == nil {
+			return errors.New("gotify settings must be set")
+		}
+		return c.Gotify.Validate()
+
+	case NotificationTypeTelegram:
+		if c.Telegram == nil {
+			return errors.New("telegram settings must be set")
+		}
+		return c.Telegram.Validate()
+
+	case NotificationTypeWebhook:
+		if c.Webhook == nil {
+			return errors.New("webhook settings must be set")
+		}
+		return c.Webhook.Validate()
+
+	case NotificationTypeDiscord:
+		if c.Discord == nil {
+			return errors.New("discord settings must be set")
+		}
+		return c.Discord.Validate()
+
+	case NotificationTypeSlack:
+		if c.Slack == nil {
+			return errors.New("slack settings must be set")
+		}
+		return c.Slack.Validate()
+
+	case NotificationTypeEmail:
+		if c.Email == nil {
+			return errors.New("email settings must be set")
+		}
+		return c.Email.Validate()
+
+	case NotificationTypeMqtt:
+		if c.Mqtt == nil {
+			return errors.New("mqtt settings must be set")
+		}
+		return c.Mqtt.Validate()
+
+	case NotificationTypeExec:
+		if c.Exec == nil {
+			return errors.New("exec settings must be set")
+		}
+		err := c.Exec.Validate()
+		if err != nil {
+			return err
+		}
+
+		// Commands are stopped when the notifier times out, so cannot run for longer
+		notifierTimeout := DefaultNotifierTimeout
+		if c.Timeout > 0 {
+			notifierTimeout = time.Duration(c.Timeout)
+		}
+		if time.Duration(c.Exec.Timeout) > notifierTimeout {
+			return fmt.Errorf("exec timeout cannot be longer than the notifier timeout of %s", notifierTimeout)
+		}
+		return nil
+
+	case NotificationTypePushover:
+		if c.Pushover == nil {
+			return errors.New("pushover settings must be set")
+		}
+		return c.Pushover.Validate()
+
+	default:
+		return errors.New("type or url must be set")
+	}
+}
+
+// MessageTemplate gets the template used to render notification messages.
+// This is either the inline template or the contents of the template file.
+// An empty string is returned if neither are set.
+func (c NotifierConfig) MessageTemplate() (string, error) {
+	if c.TemplateFile == "" {
+		return c.Template, nil
+	}
+
+	templateBytes, err := os.ReadFile(c.TemplateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	return string(templateBytes), nil
+}
+
+// templateFuncs are the functions that can be used in templates, to add markup for the format of a notifier.
+// They are only used to check templates can be parsed, as they are replaced when notifications are rendered.
+var templateFuncs = template.FuncMap{
+	"escape": fmt.Sprint,
+	"bold":   fmt.Sprint,
+	"link":   fmt.Sprint,
+}
+
+// validateTemplates checks templates can be parsed.
+// Templates are checked against the data they are rendered with when notification clients are created.
+func (c NotifierConfig) validateTemplates() error {
+	if c.Template != "" && c.TemplateFile != "" {
+		return errors.New("template and template file cannot both be set")
+	}
+
+	messageTemplate, err := c.MessageTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = template.New("message").Funcs(templateFuncs).Parse(messageTemplate)
+	if err != nil {
+		return fmt.Errorf("template is not valid: %w", err)
+	}
+
+	_, err = template.New("title").Funcs(templateFuncs).Parse(c.TitleTemplate)
+	if err != nil {
+		return fmt.Errorf("title template is not valid: %w", err)
+	}
+
+	_, err = template.New("digest").Funcs(templateFuncs).Parse(c.DigestTemplate)
+	if err != nil {
+		return fmt.Errorf("digest template is not valid: %w", err)
+	}
+
+	return nil
+}
+
+func (c NtfyConfig) Validate() error {
+	if !beginsWithHttp(c.Url) {
+		return errors.New("ntfy url must begin with 'http://' or 'https://'")
+	}
+	if c.Topic == "" {
+		return errors.New("ntfy topic must be set")
+	}
+	if c.Token != "" && (c.Username != "" || c.Password != "") {
+		return errors.New("ntfy token cannot be used with username and password")
+	}
+	if c.Priority != 0 && (c.Priority < ntfyMinPriority || c.Priority > ntfyMaxPriority) {
+		return fmt.Errorf("ntfy priority must be between %d and %d", ntfyMinPriority, ntfyMaxPriority)
+	}
+	err := validatePriorityRules(c.PriorityRules, ntfyMinPriority, ntfyMaxPriority)
+	if err != nil {
+		return fmt.Errorf("ntfy %w", err)
+	}
+	if c.Icon != "" && !beginsWithHttp(c.Icon) {
+		return errors.New("ntfy icon must begin with 'http://' or 'https://'")
+	}
+	if c.Delay != 0 && time.Duration(c.Delay) < ntfyMinDelay {
+		return fmt.Errorf("ntfy delay must be at least %s", ntfyMinDelay)
+	}
+	return nil
+}
+
+func (c GotifyConfig) Validate() error {
+	if !beginsWithHttp(c.Url) {
+		return errors.New("gotify url must begin with 'http://' or 'https://'")
+	}
+	if c.Token == "" {
+		return errors.New("gotify token cannot be empty")
+	}
+	if c.Priority != nil && (*c.Priority < gotifyMinPriority || *c.Priority > gotifyMaxPriority) {
+		return fmt.Errorf("gotify priority must be between %d and %d", gotifyMinPriority, gotifyMaxPriority)
+	}
+	err := validatePriorityRules(c.PriorityRules, gotifyMinPriority, gotifyMaxPriority)
+	if err != nil {
+		return fmt.Errorf("gotify %w", err)
+	}
+	return nil
+}
+
+func (c TelegramConfig) Validate() error {
+	if c.ChatId == 0 && len(c.Chats) == 0 {
+		return errors.New("telegram chat id or chats must be set")
+	}
+	for _, chat := range c.Chats {
+		if chat.ChatId == 0 {
 			return errors.New("telegram chat id cannot be empty")
 		}
-		if c.Telegram.Token == "" {
-			return errors.New("telegram token cannot be empty")
+		if chat.ThreadId < 0 {
+			return errors.New("telegram thread id cannot be negative")
+		}
+	}
+	if c.Token == "" {
+		return errors.New("telegram token cannot be empty")
+	}
+	if c.ApiUrl != "" && !beginsWithHttp(c.ApiUrl) {
+		return errors.New("telegram api url must begin with 'http://' or 'https://'")
+	}
+	return nil
+}
+
+// AllChats gets all the chats to send to, including chatId
+func (c TelegramConfig) AllChats() []TelegramChat {
+	chats := make([]TelegramChat, 0, len(c.Chats)+1)
+	if c.ChatId != 0 {
+		chats = append(chats, TelegramChat{ChatId: c.ChatId})
+	}
+	return append(chats, c.Chats...)
+}
+
+// IsSupergroup checks whether a chat is a supergroup, which are the only chats that can have forum topics.
+// Supergroup chat ids begin with -100.
+func (c TelegramChat) IsSupergroup() bool {
+	return strings.HasPrefix(strconv.Itoa(c.ChatId), "-100")
+}
+
+func (c WebhookConfig) Validate() error {
+	if !beginsWithHttp(c.Url) {
+		return errors.New("webhook url must begin with 'http://' or 'https://'")
+	}
+
+	_, err := template.New("body").Parse(c.BodyTemplate)
+	if err != nil {
+		return fmt.Errorf("webhook body template is not valid: %w", err)
+	}
+
+	return nil
+}
+
+func (c DiscordConfig) Validate() error {
+	if !beginsWithHttp(c.WebhookUrl) {
+		return errors.New("discord webhook url must begin with 'http://' or 'https://'")
+	}
+	return nil
+}
+
+func (c SlackConfig) Validate() error {
+	if !beginsWithHttp(c.WebhookUrl) {
+		return errors.New("slack webhook url must begin with 'http://' or 'https://'")
+	}
+	return nil
+}
+
+func (c EmailConfig) Validate() error {
+	if c.Host == "" {
+		return errors.New("email host must be set")
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return errors.New("email port must be between 1 and 65535")
+	}
+	if c.Password != "" && c.Username == "" {
+		return errors.New("email username must be set if password is set")
+	}
+	// Credentials are never sent over an unencrypted connection to hosts other than localhost
+	// See smtp.PlainAuth
+	if c.Username != "" && c.Security == EmailSecurityNone && !isLocalhost(c.Host) {
+		return errors.New("email username cannot be used with security none unless the host is localhost")
+	}
+
+	_, err := mail.ParseAddress(c.From)
+	if err != nil {
+		return fmt.Errorf("email from address '%s' is not valid: %w", c.From, err)
+	}
+
+	if len(c.To) == 0 {
+		return errors.New("email to addresses must be set")
+	}
+	for _, to := range c.To {
+		_, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("email to address '%s' is not valid: %w", to, err)
 		}
 	}
 
 	return nil
 }
 
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func (c MqttConfig) Validate() error {
+	if !hasAnyPrefix(c.Broker, "tcp://", "ssl://", "tls://", "mqtt://", "mqtts://", "ws://", "wss://") {
+		return errors.New("mqtt broker must begin with 'tcp://', 'ssl://', 'tls://', 'mqtt://', 'mqtts://', 'ws://' or 'wss://'")
+	}
+	if c.Topic == "" {
+		return errors.New("mqtt topic must be set")
+	}
+	if strings.ContainsAny(c.Topic, "+#") {
+		return errors.New("mqtt topic cannot contain wildcards")
+	}
+	if c.Qos < 0 || c.Qos > 2 {
+		return errors.New("mqtt qos must be 0, 1 or 2")
+	}
+	if c.Password != "" && c.Username == "" {
+		return errors.New("mqtt username must be set if password is set")
+	}
+	return nil
+}
+
+func (c ExecConfig) Validate() error {
+	if c.Command == "" {
+		return errors.New("exec command must be set")
+	}
+	if c.Timeout < 0 {
+		return errors.New("exec timeout cannot be negative")
+	}
+	return nil
+}
+
+func (c PushoverConfig) Validate() error {
+	if c.User == "" {
+		return errors.New("pushover user cannot be empty")
+	}
+	if c.Token == "" {
+		return errors.New("pushover token cannot be empty")
+	}
+	return nil
+}
+
 func beginsWithHttp(url string) bool {
-	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+	return hasAnyPrefix(url, "http://", "https://")
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
 }
@@ -15,16 +15,37 @@ func PrintTicketListingConfigs(configs []TicketListingConfig) {
 }
 
 func PrintTicketListingConfig(config TicketListingConfig) {
-	fmt.Printf("Event: %s\n", config.Event)
+	fmt.Print(FormatTicketListingConfig(config))
+}
+
+// FormatTicketListingConfig formats a ticket listing config as lines of text, one for each setting
+func FormatTicketListingConfig(config TicketListingConfig) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Event: %s\n", config.Event)
 
 	if config.EventSimilarity == nil || *config.EventSimilarity <= 0.0 {
-		fmt.Println("Event Similarity: Default (0.9)")
+		builder.WriteString("Event Similarity: Default (0.9)\n")
 	} else {
-		fmt.Printf("Event Similarity: %.2f%%\n", *config.EventSimilarity*100)
+		fmt.Fprintf(&builder, "Event Similarity: %.2f%%\n", *config.EventSimilarity*100)
+	}
+
+	if len(config.Countries) == 0 {
+		builder.WriteString("Countries: Any\n")
+	} else {
+
+		// Get countries as a string
+		countryStrings := make([]string, 0, len(config.Countries))
+		for _, country := range config.Countries {
+			countryStrings = append(countryStrings, country.Value)
+		}
+		countriesString := strings.Join(countryStrings, ", ")
+
+		fmt.Fprintf(&builder, "Countries: %s\n", countriesString)
 	}
 
 	if len(config.Regions) == 0 {
-		fmt.Println("Regions: Any")
+		builder.WriteString("Regions: Any\n")
 	} else {
 
 		// Get regions as a string
@@ -34,32 +55,55 @@ func PrintTicketListingConfig(config TicketListingConfig) {
 		}
 		regionsString := strings.Join(regionStrings, ", ")
 
-		fmt.Printf("Regions: %s\n", regionsString)
+		fmt.Fprintf(&builder, "Regions: %s\n", regionsString)
 	}
 
 	if config.NumTickets == nil || *config.NumTickets <= 0 {
-		fmt.Println("Number of Tickets: Any")
+		builder.WriteString("Number of Tickets: Any\n")
 	} else {
-		fmt.Printf("Number of Tickets: %d\n", *config.NumTickets)
+		fmt.Fprintf(&builder, "Number of Tickets: %d\n", *config.NumTickets)
 	}
 
 	if config.MinDiscount == nil || *config.MinDiscount <= 0.0 {
-		fmt.Println("Discount: Any")
+		builder.WriteString("Discount: Any\n")
 	} else {
-		fmt.Printf("Discount: %.0f%%\n", *config.MinDiscount)
+		fmt.Fprintf(&builder, "Discount: %.0f%%\n", *config.MinDiscount)
+	}
+
+	if config.MaxTicketPriceInclFee == nil || *config.MaxTicketPriceInclFee <= 0.0 {
+		builder.WriteString("Max Ticket Price: Any\n")
+	} else {
+		fmt.Fprintf(&builder, "Max Ticket Price: £%.2f\n", *config.MaxTicketPriceInclFee)
 	}
 
 	if len(config.Notification) == 0 {
-		fmt.Println("Notification Types: All")
+		builder.WriteString("Notifiers: All\n")
 	} else {
+		fmt.Fprintf(&builder, "Notifiers: %s\n", strings.Join(config.Notification, ", "))
+	}
 
-		// Get notifications as a string
-		notificationStrings := make([]string, 0, len(config.Notification))
-		for _, notification := range config.Notification {
-			notificationStrings = append(notificationStrings, notification.Value)
-		}
-		notificationsString := strings.Join(notificationStrings, ", ")
+	for _, rule := range config.RoutingRules {
+		fmt.Fprintf(&builder, "Routing Rule: %s\n", routingRuleString(rule))
+	}
+
+	return builder.String()
+}
 
-		fmt.Printf("Notifications: %s\n", notificationsString)
+// routingRuleString formats the conditions and notifiers of a routing rule
+func routingRuleString(rule RoutingRule) string {
+	var conditions []string
+	if rule.MinDiscount > 0 {
+		conditions = append(conditions, fmt.Sprintf("Discount >= %.0f%%", rule.MinDiscount))
+	}
+	if rule.MinTicketPrice > 0 {
+		conditions = append(conditions, fmt.Sprintf("Ticket Price >= £%.2f", rule.MinTicketPrice))
+	}
+	if rule.MaxTicketPrice > 0 {
+		conditions = append(conditions, fmt.Sprintf("Ticket Price <= £%.2f", rule.MaxTicketPrice))
 	}
+	if len(conditions) == 0 {
+		conditions = append(conditions, "Any")
+	}
+
+	return fmt.Sprintf("%s -> %s", strings.Join(conditions, ", "), strings.Join(rule.Notification, ", "))
 }
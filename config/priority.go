@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// Matches checks whether a ticket listing matches all the conditions of the rule
+func (r PriorityRule) Matches(listing twigots.TicketListing) bool {
+	return listingMatchesConditions(listing, r.MinDiscount, r.MinTicketPrice, r.MaxTicketPrice)
+}
+
+// ListingPriority gets the priority of the first rule a ticket listing matches.
+// If the listing does not match any rule, the default priority is returned.
+func ListingPriority(listing twigots.TicketListing, rules []PriorityRule, defaultPriority int) int {
+	for _, rule := range rules {
+		if rule.Matches(listing) {
+			return rule.Priority
+		}
+	}
+	return defaultPriority
+}
+
+// validatePriorityRules checks priority rules have a priority within a range, and valid conditions
+func validatePriorityRules(rules []PriorityRule, minPriority, maxPriority int) error {
+	for _, rule := range rules {
+		if rule.Priority < minPriority || rule.Priority > maxPriority {
+			return fmt.Errorf("priority rule priority must be between %d and %d", minPriority, maxPriority)
+		}
+		err := validateRuleConditions(rule.MinDiscount, rule.MinTicketPrice, rule.MaxTicketPrice)
+		if err != nil {
+			return fmt.Errorf("priority rule %w", err)
+		}
+	}
+	return nil
+}
+
+// listingMatchesConditions checks whether a ticket listing matches the conditions of a rule.
+// Conditions that are not set (zero) always match.
+func listingMatchesConditions(
+	listing twigots.TicketListing,
+	minDiscount, minTicketPrice, maxTicketPrice float64,
+) bool {
+	if minDiscount > 0 && listing.Discount()*100 < minDiscount {
+		return false
+	}
+
+	ticketPrice := listing.TicketPriceInclFee().Number()
+	if minTicketPrice > 0 && ticketPrice < minTicketPrice {
+		return false
+	}
+	if maxTicketPrice > 0 && ticketPrice > maxTicketPrice {
+		return false
+	}
+
+	return true
+}
+
+// validateRuleConditions checks the conditions of a rule are valid
+func validateRuleConditions(minDiscount, minTicketPrice, maxTicketPrice float64) error {
+	if minDiscount < 0 || minTicketPrice < 0 || maxTicketPrice < 0 {
+		return errors.New("discount and prices cannot be negative")
+	}
+	if maxTicketPrice > 0 && minTicketPrice > maxTicketPrice {
+		return errors.New("min ticket price cannot be more than max ticket price")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// Layout of the start and end times of quiet hours
+const quietHoursTimeLayout = "15:04"
+
+func (q QuietHoursConfig) Validate() error {
+	start, err := time.Parse(quietHoursTimeLayout, q.Start)
+	if err != nil {
+		return errors.New("quiet hours start must be a time in HH:MM format")
+	}
+
+	end, err := time.Parse(quietHoursTimeLayout, q.End)
+	if err != nil {
+		return errors.New("quiet hours end must be a time in HH:MM format")
+	}
+
+	if start.Equal(end) {
+		return errors.New("quiet hours start and end cannot be the same")
+	}
+
+	_, err = q.Location()
+	if err != nil {
+		return fmt.Errorf("quiet hours timezone '%s' is not valid", q.Timezone)
+	}
+
+	if q.BypassDiscount < 0 {
+		return errors.New("quiet hours bypass discount cannot be negative")
+	}
+
+	return nil
+}
+
+// Location gets the timezone of the start and end times. If not set, the local timezone is used.
+func (q QuietHoursConfig) Location() (*time.Location, error) {
+	if q.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(q.Timezone)
+}
+
+// EndsAt gets the time the quiet hours a time is in end.
+// If the time is not in quiet hours, false is returned.
+// Quiet hours that end before they start span midnight e.g. 22:00 to 07:00.
+func (q QuietHoursConfig) EndsAt(at time.Time) (time.Time, bool) {
+	location, err := q.Location()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	startClock, err := time.Parse(quietHoursTimeLayout, q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	endClock, err := time.Parse(quietHoursTimeLayout, q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// Quiet hours containing the time either started on the same day, or the day before if they span midnight
+	at = at.In(location)
+	for _, days := range []int{-1, 0} {
+		year, month, day := at.AddDate(0, 0, days).Date()
+		start := time.Date(year, month, day, startClock.Hour(), startClock.Minute(), 0, 0, location)
+		end := time.Date(year, month, day, endClock.Hour(), endClock.Minute(), 0, 0, location)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+
+		if !at.Before(start) && at.Before(end) {
+			return end, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Bypasses checks whether a ticket listing has a big enough discount to be sent during quiet hours
+func (q QuietHoursConfig) Bypasses(listing twigots.TicketListing) bool {
+	return q.BypassDiscount > 0 && listing.Discount()*100 >= q.BypassDiscount
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/samber/lo"
+)
+
+// Matches checks whether a ticket listing matches all the conditions of the rule
+func (r RoutingRule) Matches(listing twigots.TicketListing) bool {
+	return listingMatchesConditions(listing, r.MinDiscount, r.MinTicketPrice, r.MaxTicketPrice)
+}
+
+// ListingNotifiers gets the names of the notifiers to notify about a ticket listing.
+// These are the notifiers of the first routing rule the listing matches,
+// or the notifiers of the config if the listing does not match any rule.
+func (c TicketListingConfig) ListingNotifiers(listing twigots.TicketListing) []string {
+	for _, rule := range c.RoutingRules {
+		if rule.Matches(listing) {
+			return rule.Notification
+		}
+	}
+	return c.Notification
+}
+
+// UsesNotifier checks whether a notifier could be notified about ticket listings,
+// either by default or by a routing rule. A config with no notifiers uses all of them.
+func (c TicketListingConfig) UsesNotifier(name string) bool {
+	if len(c.Notification) == 0 || lo.Contains(c.Notification, name) {
+		return true
+	}
+	return lo.ContainsBy(c.RoutingRules, func(rule RoutingRule) bool {
+		return lo.Contains(rule.Notification, name)
+	})
+}
+
+// validateRoutingRules checks routing rules have valid conditions, and notifiers that exist
+func validateRoutingRules(notifierNames map[string]struct{}, rules []RoutingRule) error {
+	for _, rule := range rules {
+		err := validateRuleConditions(rule.MinDiscount, rule.MinTicketPrice, rule.MaxTicketPrice)
+		if err != nil {
+			return fmt.Errorf("routing rule %w", err)
+		}
+		if len(rule.Notification) == 0 {
+			return errors.New("routing rule must have at least one notifier")
+		}
+		err = validateNotifierNames(notifierNames, rule.Notification)
+		if err != nil {
+			return fmt.Errorf("routing rule is not valid: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Host used by ntfy urls without a host e.g. ntfy://topic
+const defaultNtfyHost = "ntfy.sh"
+
+// ExpandUrl gets the notifier with the type and settings from its url.
+// If the notifier does not have a url, it is returned unchanged.
+func (c NotifierConfig) ExpandUrl() (NotifierConfig, error) {
+	if c.Url == "" {
+		return c, nil
+	}
+
+	parsed, err := ParseNotifierUrl(c.Url)
+	if err != nil {
+		return NotifierConfig{}, err
+	}
+
+	// Keep the settings shared by all types, replacing the url with the type and its settings
+	expanded := c
+	expanded.Url = ""
+	expanded.Type = parsed.Type
+	expanded.Ntfy = parsed.Ntfy
+	expanded.Gotify = parsed.Gotify
+	expanded.Telegram = parsed.Telegram
+	expanded.Webhook = parsed.Webhook
+	expanded.Discord = parsed.Discord
+	expanded.Slack = parsed.Slack
+	expanded.Email = parsed.Email
+	expanded.Mqtt = parsed.Mqtt
+	expanded.Exec = parsed.Exec
+	expanded.Pushover = parsed.Pushover
+
+	return expanded, nil
+}
+
+// ParseNotifierUrl parses the url of a notification service into a notifier with its type and settings.
+// The format of urls is similar to apprise. Supported urls are:
+//   - pushover://user@token?device=phone
+//   - gotify://host/token (http) or gotifys://host/token (https)
+//   - ntfy://host/topic (http) or ntfys://host/topic (https). ntfy://topic uses ntfy.sh.
+//     Use user:password@host for basic auth, or token@host for an access token
+//   - tgram://token/chatId. More chats can be added e.g. tgram://token/chatId/chatId:threadId
+//   - discord://webhookId/webhookToken?username=name
+//   - slack://tokenA/tokenB/tokenC
+//   - json://host/path (http) or jsons://host/path (https), for webhooks
+func ParseNotifierUrl(notifierUrl string) (NotifierConfig, error) {
+	scheme, _, found := strings.Cut(notifierUrl, "://")
+	if !found {
+		return NotifierConfig{}, errors.New("url must begin with a scheme e.g. pushover://")
+	}
+
+	// Telegram tokens contain a colon, so cannot be parsed as a url host
+	if scheme == "tgram" {
+		return parseTelegramUrl(notifierUrl)
+	}
+
+	parsedUrl, err := url.Parse(notifierUrl)
+	if err != nil {
+		return NotifierConfig{}, fmt.Errorf("url is not valid: %w", err)
+	}
+	pathParts := splitUrlPath(parsedUrl.Path)
+
+	switch scheme {
+	case "pushover":
+		if parsedUrl.User == nil || parsedUrl.Host == "" {
+			return NotifierConfig{}, errors.New("pushover url must be in the form pushover://user@token")
+		}
+		return NotifierConfig{
+			Type: NotificationTypePushover,
+			Pushover: &PushoverConfig{
+				User:   parsedUrl.User.Username(),
+				Token:  parsedUrl.Host,
+				Device: parsedUrl.Query().Get("device"),
+			},
+		}, nil
+
+	case "gotify", "gotifys":
+		if parsedUrl.Host == "" || len(pathParts) == 0 {
+			return NotifierConfig{}, fmt.Errorf("gotify url must be in the form %s://host/token", scheme)
+		}
+		token := pathParts[len(pathParts)-1]
+		basePath := strings.Join(pathParts[:len(pathParts)-1], "/")
+		return NotifierConfig{
+			Type: NotificationTypeGotify,
+			Gotify: &GotifyConfig{
+				Url:   httpUrl(scheme == "gotifys", parsedUrl.Host, basePath),
+				Token: token,
+			},
+		}, nil
+
+	case "ntfy", "ntfys":
+		host := parsedUrl.Host
+		topic := strings.Join(pathParts, "/")
+		secure := scheme == "ntfys"
+		if topic == "" {
+			// Only a topic is given, so use the public instance
+			host, topic = defaultNtfyHost, parsedUrl.Host
+			secure = true
+		}
+		if topic == "" {
+			return NotifierConfig{}, fmt.Errorf("ntfy url must be in the form %s://host/topic", scheme)
+		}
+
+		ntfyConfig := &NtfyConfig{
+			Url:   httpUrl(secure, host, ""),
+			Topic: topic,
+		}
+		if parsedUrl.User != nil {
+			// A user without a password is an access token
+			password, hasPassword := parsedUrl.User.Password()
+			if hasPassword {
+				ntfyConfig.Username = parsedUrl.User.Username()
+				ntfyConfig.Password = password
+			} else {
+				ntfyConfig.Token = parsedUrl.User.Username()
+			}
+		}
+		return NotifierConfig{Type: NotificationTypeNtfy, Ntfy: ntfyConfig}, nil
+
+	case "discord":
+		if parsedUrl.Host == "" || len(pathParts) != 1 {
+			return NotifierConfig{}, errors.New("discord url must be in the form discord://webhookId/webhookToken")
+		}
+		return NotifierConfig{
+			Type: NotificationTypeDiscord,
+			Discord: &DiscordConfig{
+				WebhookUrl: fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", parsedUrl.Host, pathParts[0]),
+				Username:   parsedUrl.Query().Get("username"),
+			},
+		}, nil
+
+	case "slack":
+		if parsedUrl.Host == "" || len(pathParts) != 2 {
+			return NotifierConfig{}, errors.New("slack url must be in the form slack://tokenA/tokenB/tokenC")
+		}
+		return NotifierConfig{
+			Type: NotificationTypeSlack,
+			Slack: &SlackConfig{
+				WebhookUrl: fmt.Sprintf("https://hooks.slack.com/services/%s/%s/%s", parsedUrl.Host, pathParts[0], pathParts[1]),
+			},
+		}, nil
+
+	case "json", "jsons":
+		if parsedUrl.Host == "" {
+			return NotifierConfig{}, fmt.Errorf("json url must be in the form %s://host/path", scheme)
+		}
+		webhookUrl := url.URL{
+			Scheme:   "http",
+			User:     parsedUrl.User,
+			Host:     parsedUrl.Host,
+			Path:     parsedUrl.Path,
+			RawQuery: parsedUrl.RawQuery,
+		}
+		if scheme == "jsons" {
+			webhookUrl.Scheme = "https"
+		}
+		return NotifierConfig{
+			Type:    NotificationTypeWebhook,
+			Webhook: &WebhookConfig{Url: webhookUrl.String()},
+		}, nil
+
+	default:
+		return NotifierConfig{}, fmt.Errorf("url scheme '%s' is not supported", scheme)
+	}
+}
+
+func parseTelegramUrl(notifierUrl string) (NotifierConfig, error) {
+	parts := splitUrlPath(strings.TrimPrefix(notifierUrl, "tgram://"))
+	if len(parts) < 2 {
+		return NotifierConfig{}, errors.New("telegram url must be in the form tgram://token/chatId")
+	}
+
+	telegramConfig := &TelegramConfig{Token: parts[0]}
+	for _, chatPart := range parts[1:] {
+		chat, err := parseTelegramUrlChat(chatPart)
+		if err != nil {
+			return NotifierConfig{}, err
+		}
+
+		// Use chat id if possible, so the config is as simple as possible
+		if len(parts) == 2 && chat.ThreadId == 0 {
+			telegramConfig.ChatId = chat.ChatId
+		} else {
+			telegramConfig.Chats = append(telegramConfig.Chats, chat)
+		}
+	}
+
+	return NotifierConfig{
+		Type:     NotificationTypeTelegram,
+		Telegram: telegramConfig,
+	}, nil
+}
+
+// parseTelegramUrlChat parses a chat in a telegram url, in the form chatId or chatId:threadId
+func parseTelegramUrlChat(chatPart string) (TelegramChat, error) {
+	chatIdString, threadIdString, hasThread := strings.Cut(chatPart, ":")
+
+	chatId, err := strconv.Atoi(chatIdString)
+	if err != nil {
+		return TelegramChat{}, fmt.Errorf("telegram chat id '%s' is not a number", chatIdString)
+	}
+
+	chat := TelegramChat{ChatId: chatId}
+	if hasThread {
+		chat.ThreadId, err = strconv.Atoi(threadIdString)
+		if err != nil {
+			return TelegramChat{}, fmt.Errorf("telegram thread id '%s' is not a number", threadIdString)
+		}
+	}
+
+	return chat, nil
+}
+
+// splitUrlPath splits a url path into its parts, ignoring empty parts
+func splitUrlPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// httpUrl creates a http or https url from a host and path
+func httpUrl(secure bool, host, path string) string {
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	httpUrl := scheme + "://" + host
+	if path != "" {
+		httpUrl += "/" + path
+	}
+	return httpUrl
+}
@@ -27,14 +27,14 @@ function newNtfyConfig(): NtfyConfig {
 export function NotificationSettings() {
   const { config, updateConfig } = useConfig();
 
-  const [draft, setDraft] = useState(config.notification);
+  const [draft, setDraft] = useState(config.notification ?? {});
   const [showNtfyPassword, setShowNtfyPassword] = useState(false);
 
   useEffect(() => {
-    setDraft(config.notification);
+    setDraft(config.notification ?? {});
   }, [config.notification]);
 
-  const hasChanges = !isEqual(draft, config.notification);
+  const hasChanges = !isEqual(draft, config.notification ?? {});
 
   const toggleShowNtfyPassword = () => {
     setShowNtfyPassword(!showNtfyPassword);
@@ -67,7 +67,7 @@ export function NotificationSettings() {
               });
             }}
             onDiscard={() => {
-              setDraft(config.notification);
+              setDraft(config.notification ?? {});
             }}
           />
         )
@@ -89,6 +89,107 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/notifications/dead-letters": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get dead letter notifications
+         * @description Retrieve notifications that failed to be sent after running out of retry attempts
+         */
+        get: {
+            parameters: {
+                query?: never;
+                header?: never;
+                path?: never;
+                cookie?: never;
+            };
+            requestBody?: never;
+            responses: {
+                /** @description Successful response */
+                200: {
+                    headers: {
+                        [name: string]: unknown;
+                    };
+                    content: {
+                        "application/json": components["schemas"]["DeadLetter"][];
+                    };
+                };
+                /** @description Internal server error */
+                500: {
+                    headers: {
+                        [name: string]: unknown;
+                    };
+                    content?: never;
+                };
+            };
+        };
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/notifications/dead-letters/{id}/resend": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Resend a dead letter notification
+         * @description Queue a dead letter notification to be sent again, with a full set of retry attempts.
+         *     The notification is removed from the dead letters.
+         */
+        post: {
+            parameters: {
+                query?: never;
+                header?: never;
+                path: {
+                    /** @description Dead letter id */
+                    id: string;
+                };
+                cookie?: never;
+            };
+            requestBody?: never;
+            responses: {
+                /** @description Notification queued to be sent */
+                202: {
+                    headers: {
+                        [name: string]: unknown;
+                    };
+                    content?: never;
+                };
+                /** @description Dead letter not found */
+                404: {
+                    headers: {
+                        [name: string]: unknown;
+                    };
+                    content?: never;
+                };
+                /** @description Internal server error */
+                500: {
+                    headers: {
+                        [name: string]: unknown;
+                    };
+                    content?: never;
+                };
+            };
+        };
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
 }
 export type webhooks = Record<string, never>;
 export interface components {
@@ -99,6 +200,91 @@ export interface components {
          * @enum {string}
          */
         Country: "GB";
+        /**
+         * @description A named notification service.
+         *     Either type and the settings of the type, or url should be set.
+         */
+        NotifierConfig: {
+            /** @description Unique name of the notifier, used to choose which notifiers to use for tickets */
+            name: string;
+            /**
+             * @description Type of the notification service.
+             *     Cannot be used with url.
+             */
+            type?: components["schemas"]["NotificationType"];
+            /**
+             * @description URL of the notification service, instead of type and its settings e.g. pushover://user@token.
+             *     Cannot be used with type. See README.md for the supported URLs.
+             */
+            url?: string;
+            ntfy?: components["schemas"]["NtfyConfig"];
+            gotify?: components["schemas"]["GotifyConfig"];
+            telegram?: components["schemas"]["TelegramConfig"];
+            webhook?: components["schemas"]["WebhookConfig"];
+            discord?: components["schemas"]["DiscordConfig"];
+            slack?: components["schemas"]["SlackConfig"];
+            email?: components["schemas"]["EmailConfig"];
+            mqtt?: components["schemas"]["MqttConfig"];
+            exec?: components["schemas"]["ExecConfig"];
+            pushover?: components["schemas"]["PushoverConfig"];
+            /**
+             * @description Go template used to render notification messages (Optional).
+             *     Cannot be used with templateFile.
+             *     Default: Built in template.
+             */
+            template?: string;
+            /**
+             * @description Path of a file containing a Go template used to render notification messages (Optional).
+             *     Cannot be used with template.
+             */
+            templateFile?: string;
+            /**
+             * @description Go template used to render notification titles (Optional).
+             *     Not all notification types have titles.
+             *     Default: Event name.
+             */
+            titleTemplate?: string;
+            /**
+             * @description Go template used to render digests, which combine the notifications of several listings (Optional).
+             *     Cannot be used by webhook, mqtt and exec notifiers, which send each listing on its own.
+             *     Default: Built in template.
+             */
+            digestTemplate?: string;
+            quietHours?: components["schemas"]["QuietHoursConfig"];
+            /**
+             * @description Time to wait after a listing for an event is found before notifying, e.g. 2m (Optional).
+             *     Listings for the same event found while waiting are combined into one digest.
+             *     Webhook, mqtt and exec notifiers send each listing on its own instead.
+             *     Default: Notify straight away.
+             */
+            batchWindow?: string;
+            /**
+             * @description Maximum time to wait for a notification to be sent, e.g. 1m (Optional).
+             *     Default: 30s.
+             */
+            timeout?: string;
+        };
+        /**
+         * @description Hours when notifications are held rather than sent (Optional).
+         *     Held notifications are sent as one digest when quiet hours end.
+         */
+        QuietHoursConfig: {
+            /** @description Time quiet hours start, in 24 hour HH:MM format e.g. 22:00 */
+            start: string;
+            /** @description Time quiet hours end, in 24 hour HH:MM format e.g. 07:00 */
+            end: string;
+            /**
+             * @description Timezone of the start and end times e.g. Europe/London (Optional).
+             *     Default: Local timezone.
+             */
+            timezone?: string;
+            /**
+             * Format: double
+             * @description Minimum discount on the original price as a percentage for listings to be sent during quiet hours (Optional).
+             *     Default: All listings are held.
+             */
+            bypassDiscount?: number;
+        };
         NtfyConfig: {
             /** @description You can use the public instance at https://ntfy.sh */
             url: string;
@@ -108,25 +294,240 @@ export interface components {
             username?: string;
             /** @description Password for authenticated instances (Optional) */
             password?: string;
+            /**
+             * @description Access token for authenticated instances e.g. tk_... (Optional).
+             *     Use instead of username and password.
+             */
+            token?: string;
+            /**
+             * @description Priority of notifications, from 1 (min) to 5 (max) (Optional).
+             *     Default: 3.
+             */
+            priority?: number;
+            /**
+             * @description Priorities of notifications for listings with a discount or price (Optional).
+             *     The first rule the listing matches is used, otherwise priority is used.
+             */
+            priorityRules?: components["schemas"]["PriorityRule"][];
+            /** @description Tags of notifications, which can be emojis e.g. tada (Optional) */
+            tags?: string[];
+            /** @description URL of an icon to show with notifications (Optional) */
+            icon?: string;
+            /**
+             * @description Time to delay notifications by e.g. 30m (Optional).
+             *     Must be at least 10s.
+             */
+            delay?: string;
+        };
+        /**
+         * @description A priority used for listings matching all the conditions of the rule.
+         *     Conditions that are not set always match.
+         */
+        PriorityRule: {
+            /** @description Priority to use */
+            priority: number;
+            /**
+             * Format: double
+             * @description Minimum discount on the original price as a percentage (Optional)
+             */
+            minDiscount?: number;
+            /**
+             * Format: double
+             * @description Minimum price per ticket (including fee) in pounds (£) (Optional)
+             */
+            minTicketPrice?: number;
+            /**
+             * Format: double
+             * @description Maximum price per ticket (including fee) in pounds (£) (Optional)
+             */
+            maxTicketPrice?: number;
         };
         GotifyConfig: {
             /** @description Your Gotify server URL */
             url: string;
-            /** @description Application token from Gotify */
+            /**
+             * @description Application token from Gotify.
+             *     The token is checked when twitchets starts, or the config is reloaded.
+             */
             token: string;
+            /**
+             * @description Priority of notifications, from 0 (min) to 10 (max) (Optional).
+             *     Default: 5.
+             */
+            priority?: number;
+            /**
+             * @description Priorities of notifications for listings with a discount or price (Optional).
+             *     The first rule the listing matches is used, otherwise priority is used.
+             */
+            priorityRules?: components["schemas"]["PriorityRule"][];
         };
         TelegramConfig: {
             /** @description Get from @BotFather on Telegram */
             token: string;
-            /** @description Your chat ID or group chat ID */
+            /**
+             * @description Your chat ID or group chat ID.
+             *     Use chats instead to send to more than one chat, or to a forum topic.
+             */
+            chatId?: number;
+            /** @description Chats to send to, in addition to chatId (Optional) */
+            chats?: components["schemas"]["TelegramChat"][];
+            /**
+             * @description Formatting used by messages (Optional).
+             *     Custom templates must be written using this formatting.
+             *     Default: html.
+             */
+            parseMode?: components["schemas"]["TelegramParseMode"];
+            /**
+             * @description URL of the Telegram Bot API server, if using your own (Optional).
+             *     Default: https://api.telegram.org.
+             */
+            apiUrl?: string;
+            /**
+             * @description Whether to handle commands sent to the bot from the chat, to manage the tickets being watched (Optional, Requires restart).
+             *     See README.md for the supported commands.
+             */
+            commands?: boolean;
+        };
+        /** @description A telegram chat to send to */
+        TelegramChat: {
+            /** @description Chat ID or group chat ID */
             chatId: number;
+            /**
+             * @description ID of the forum topic (message_thread_id) to send to, for groups with topics (Optional).
+             *     Default: The general topic.
+             */
+            threadId?: number;
         };
+        /**
+         * @description Formatting used by telegram messages.
+         *     - html: Telegram HTML e.g. <b>bold</b>.
+         *     - markdownv2: Telegram MarkdownV2 e.g. *bold*.
+         * @enum {string}
+         */
+        TelegramParseMode: "html" | "markdownv2";
         /** @description Notification service configuration */
         NotificationConfig: {
             ntfy?: components["schemas"]["NtfyConfig"];
             gotify?: components["schemas"]["GotifyConfig"];
             telegram?: components["schemas"]["TelegramConfig"];
         };
+        WebhookConfig: {
+            /** @description URL to POST notifications to */
+            url: string;
+            /** @description Headers to add to requests (Optional) */
+            headers?: {
+                [key: string]: string;
+            };
+            /**
+             * @description Secret used to sign requests (Optional).
+             *     If set, requests have a X-Twitchets-Signature header containing sha256=<HMAC-SHA256 of the body in hex>.
+             */
+            secret?: string;
+            /**
+             * @description Go template used to render the request body, instead of the JSON payload (Optional).
+             *     The template is rendered with the JSON payload.
+             */
+            bodyTemplate?: string;
+        };
+        DiscordConfig: {
+            /** @description Discord webhook URL. Create one in the integrations settings of a channel */
+            webhookUrl: string;
+            /** @description Name to send messages as, instead of the webhook name (Optional) */
+            username?: string;
+        };
+        SlackConfig: {
+            /** @description Slack incoming webhook URL. See https://api.slack.com/messaging/webhooks */
+            webhookUrl: string;
+        };
+        EmailConfig: {
+            /** @description SMTP server host */
+            host: string;
+            /** @description SMTP server port e.g. 587 for STARTTLS, 465 for TLS */
+            port: number;
+            /**
+             * @description How to secure the connection to the SMTP server (Optional).
+             *     Default: starttls.
+             */
+            security?: components["schemas"]["EmailSecurity"];
+            /** @description Username for authenticated SMTP servers (Optional) */
+            username?: string;
+            /** @description Password for authenticated SMTP servers (Optional) */
+            password?: string;
+            /** @description Address to send emails from */
+            from: string;
+            /** @description Addresses to send emails to */
+            to: string[];
+        };
+        /**
+         * @description How to secure the connection to an SMTP server.
+         *     - none: Do not secure the connection. Only use this for servers on your own network.
+         *       A username and password can only be used with this if the host is localhost.
+         *     - starttls: Upgrade the connection to TLS using STARTTLS.
+         *     - tls: Connect using TLS (implicit TLS).
+         * @enum {string}
+         */
+        EmailSecurity: "none" | "starttls" | "tls";
+        MqttConfig: {
+            /**
+             * @description MQTT broker URL e.g. tcp://localhost:1883.
+             *     Use ssl:// for TLS, or ws:// and wss:// for websockets.
+             */
+            broker: string;
+            /** @description Topic to publish matches to */
+            topic: string;
+            /**
+             * @description Client ID to connect with (Optional).
+             *     Default: twitchets.
+             */
+            clientId?: string;
+            /** @description Username for authenticated brokers (Optional) */
+            username?: string;
+            /** @description Password for authenticated brokers (Optional) */
+            password?: string;
+            /**
+             * @description Quality of service to publish with. One of 0, 1 or 2 (Optional).
+             *     Default: 0.
+             */
+            qos?: number;
+            /** @description Whether the broker should retain published matches (Optional) */
+            retain?: boolean;
+            /**
+             * @description Whether to publish Home Assistant MQTT discovery configs (Optional).
+             *     This creates a "last match" sensor for each event.
+             */
+            homeAssistantDiscovery?: boolean;
+            /**
+             * @description Home Assistant MQTT discovery prefix (Optional).
+             *     Default: homeassistant.
+             */
+            discoveryPrefix?: string;
+        };
+        ExecConfig: {
+            /**
+             * @description Command to run e.g. /scripts/notify.sh.
+             *     The command is run directly, not in a shell.
+             */
+            command: string;
+            /** @description Arguments to run the command with (Optional) */
+            args?: string[];
+            /**
+             * @description Maximum time the command can run for before it is stopped e.g. 10s (Optional).
+             *     Cannot be longer than the timeout of the notifier.
+             *     Default: 30s.
+             */
+            timeout?: string;
+        };
+        PushoverConfig: {
+            /** @description Your user or group key, shown on your Pushover dashboard */
+            user: string;
+            /** @description API token of your Pushover application */
+            token: string;
+            /**
+             * @description Device to send notifications to (Optional).
+             *     Default: All of your devices.
+             */
+            device?: string;
+        };
         /**
          * @description Region code.
          *     Possible values are:
@@ -146,7 +547,7 @@ export interface components {
          */
         Region: "GBLO" | "GBSO" | "GBSW" | "GBSE" | "GBMI" | "GBEA" | "GBNO" | "GBNE" | "GBNW" | "GBSC" | "GBWA" | "GBNI";
         /** @enum {string} */
-        NotificationType: "ntfy" | "gotify" | "telegram";
+        NotificationType: "ntfy" | "gotify" | "telegram" | "webhook" | "discord" | "slack" | "email" | "mqtt" | "exec" | "pushover";
         /**
          * @description GlobalTicketListingConfig represents configuration settings that apply to all ticket listings
          *     unless explicitly overridden by a specific ticket configuration.
@@ -159,8 +560,15 @@ export interface components {
              *     Default: 0.9 (allows for minor naming differences)
              */
             eventSimilarity?: number;
+            /**
+             * @description Countries to search for tickets.
+             *     Countries must be scanned, i.e. be either country or one of countries.
+             *     Default: All scanned countries if not specified.
+             */
+            countries?: components["schemas"]["Country"][];
             /**
              * @description Geographic regions to search for tickets.
+             *     Regions must be in one of the countries being searched.
              *     Default: All regions if not specified.
              *     Full list: https://github.com/ahobsonsayers/twigots/blob/main/location.go#L79-L90
              */
@@ -183,13 +591,45 @@ export interface components {
              */
             maxTicketPrice?: number;
             /**
-             * @description Notification services to use
-             *     Default: All configured services.
+             * @description Names of notifiers to use
+             *     Default: All configured notifiers.
+             */
+            notification?: string[];
+            /**
+             * @description Rules choosing the notifiers to use for listings with a discount or price.
+             *     Rules are checked in order, and the notifiers of the first rule the listing matches are used.
+             *     If the listing does not match any rule, notification is used.
+             *     Default: No rules.
              */
-            notification?: components["schemas"]["NotificationType"][];
+            routingRules?: components["schemas"]["RoutingRules"];
         };
+        Countries: components["schemas"]["Country"][];
         Regions: components["schemas"]["Region"][];
-        Notifications: components["schemas"]["NotificationType"][];
+        Notifications: string[];
+        RoutingRules: components["schemas"]["RoutingRule"][];
+        /**
+         * @description A rule choosing the notifiers to use for listings matching all the conditions of the rule.
+         *     Conditions that are not set always match.
+         */
+        RoutingRule: {
+            /**
+             * Format: double
+             * @description Minimum discount on the original price as a percentage (Optional)
+             */
+            minDiscount?: number;
+            /**
+             * Format: double
+             * @description Minimum price per ticket (including fee) in pounds (£) (Optional)
+             */
+            minTicketPrice?: number;
+            /**
+             * Format: double
+             * @description Maximum price per ticket (including fee) in pounds (£) (Optional)
+             */
+            maxTicketPrice?: number;
+            /** @description Names of notifiers to use */
+            notification: components["schemas"]["Notifications"];
+        };
         /**
          * @description TicketListingConfig represents configuration for specific ticket listings
          *     Configuration overrides global configuration
@@ -207,6 +647,11 @@ export interface components {
              *     Overrides global setting.
              */
             eventSimilarity?: number;
+            /**
+             * @description Countries to search for tickets
+             *     Overrides global setting. To reset to default (all scanned countries), use an empty array [].
+             */
+            countries?: components["schemas"]["Countries"];
             /**
              * @description Geographic regions to search for tickets
              *     Overrides global setting. To reset to default (all regions), use an empty array [].
@@ -230,21 +675,93 @@ export interface components {
              */
             maxTicketPrice?: number;
             /**
-             * @description Notification services to use
-             *     Overrides global setting. To reset to default (all configured services), use an empty array [].
+             * @description Names of notifiers to use
+             *     Overrides global setting. To reset to default (all configured notifiers), use an empty array [].
              */
             notification?: components["schemas"]["Notifications"];
+            /**
+             * @description Rules choosing the notifiers to use for listings with a discount or price
+             *     Overrides global setting. To reset to default (no rules), use an empty array [].
+             */
+            routingRules?: components["schemas"]["RoutingRules"];
+            /**
+             * @description ID of the forum topic (message_thread_id) to send telegram notifications for these tickets to (Optional)
+             *     e.g. to have a topic for each artist. Only used for the chat set by telegramChatId.
+             *     Must be set with telegramChatId.
+             */
+            telegramThreadId?: number;
+            /**
+             * @description ID of the telegram supergroup chat the forum topic of telegramThreadId belongs to (Optional).
+             *     Other chats are sent to using their own topic.
+             *     Must be set with telegramThreadId.
+             */
+            telegramChatId?: number;
         };
         Config: {
             /** @description REQUIRED: See README.md for details on how to obtain */
             apiKey: string;
             country: components["schemas"]["Country"];
+            /** @description Additional countries to scan for tickets, alongside country (Optional). */
+            countries?: components["schemas"]["Country"][];
+            /**
+             * @description How often to scan for new ticket listings e.g. 30s, 1m.
+             *     Must be at least 5s. Changes take effect without a restart.
+             *     Default: 1m.
+             */
+            scanInterval?: string;
+            /**
+             * @description Maximum random time added to each scan interval, to avoid scanning at regular times e.g. 10s.
+             *     Default: No jitter.
+             */
+            scanJitter?: string;
+            /**
+             * @description Maximum time to wait between scans when scanning keeps failing.
+             *     The time between scans doubles after each consecutive failure, up to this maximum.
+             *     Default: 15m.
+             */
+            scanMaxBackoff?: string;
             /** @description URL of FlareSolverr proxy server for bypassing Cloudflare (Optional) */
             flaresolverrUrl?: string;
-            notification: components["schemas"]["NotificationConfig"];
+            /**
+             * @description Path of the file used to store state that must survive restarts,
+             *     such as listings that have already been notified (Optional, Requires restart).
+             *     Default: state.db in the working directory.
+             */
+            statePath?: string;
+            /**
+             * @description Whether notifications are paused (Optional).
+             *     Tickets are still scanned for while paused, but no notifications are sent for them.
+             */
+            paused?: boolean;
+            /**
+             * @description Notification services, one of each type (Optional).
+             *     Each service is a notifier named after its type e.g. ntfy.
+             *     Use notifiers to configure more than one service of the same type.
+             */
+            notification?: components["schemas"]["NotificationConfig"];
+            /**
+             * @description Named notification services (Optional).
+             *     Names must be unique, including the names of notification services configured in notification.
+             */
+            notifiers?: components["schemas"]["NotifierConfig"][];
             global: components["schemas"]["GlobalTicketListingConfig"];
             tickets: components["schemas"]["TicketListingConfig"][];
         };
+        DeadLetter: {
+            id: string;
+            /** @description Notifier that failed to send the notification */
+            notifier: string;
+            listingId: string;
+            eventName: string;
+            /** @description Link to the ticket listing */
+            link: string;
+            /** @description Number of attempts made to send the notification */
+            attempts: number;
+            /** @description Error of the last attempt to send the notification */
+            lastError: string;
+            /** Format: date-time */
+            lastAttemptAt: string;
+        };
     };
     responses: never;
     parameters: never;
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
+	"syscall"
 	"time"
+	_ "time/tzdata" // Timezones of quiet hours, as the docker image does not include them
 
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
@@ -16,13 +20,18 @@ import (
 	"github.com/ahobsonsayers/twitchets/notification"
 	"github.com/ahobsonsayers/twitchets/scanner"
 	"github.com/ahobsonsayers/twitchets/server"
+	"github.com/ahobsonsayers/twitchets/store"
 	"github.com/joho/godotenv"
 )
 
 //go:generate go tool oapi-codegen -config ./oapi.models.yaml ./schema/models.openapi.yaml
 //go:generate go tool oapi-codegen -config ./oapi.server.yaml ./schema/server.openapi.yaml
 
-const refetchTime = 1 * time.Minute
+const (
+	defaultScanInterval   = 1 * time.Minute
+	defaultScanMaxBackoff = 15 * time.Minute
+	defaultStateFile      = "state.db"
+)
 
 func init() {
 	_ = godotenv.Load()
@@ -57,8 +66,18 @@ func main() {
 	// Print the tickets being scanned for
 	config.PrintTicketListingConfigs(ticketScannerConfig.ListingConfigs)
 
+	// Open state store
+	statePath := userConfig.StatePath
+	if statePath == "" {
+		statePath = filepath.Join(cwd, defaultStateFile)
+	}
+	stateStore, err := store.Open(statePath)
+	if err != nil {
+		log.Fatalf("state store error: %v", err)
+	}
+
 	// Create ticket scanner
-	ticketScanner := scanner.NewTicketScanner(ticketScannerConfig)
+	ticketScanner := scanner.NewTicketScanner(ticketScannerConfig, stateStore)
 
 	// Watch config file for changes (in a goroutine)
 	go func() {
@@ -71,19 +90,35 @@ func main() {
 		}
 	}()
 
-	// Start scanner in gorountine
-	log.Println("Scanning for tickets...")
+	// Run server (in a goroutine)
 	go func() {
-		err = ticketScanner.Start(context.Background())
+		err := server.Start(9000, frontend.DistFS, userConfigPath, stateStore)
 		if err != nil {
-			log.Fatalf("error running scanner: %v", err)
+			log.Fatalf("error running server: %v", err)
 		}
 	}()
 
-	// Run server
-	err = server.Start(9000, frontend.DistFS, userConfigPath)
+	// Stop scanning on interrupt or termination, so in-flight notifications can be sent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	// Handle telegram commands (in goroutines)
+	err = startTelegramCommandHandlers(ctx, userConfig, userConfigPath, stateStore)
+	if err != nil {
+		log.Fatalf("telegram commands error: %v", err)
+	}
+
+	// Run scanner - blocks until stopped
+	log.Println("Scanning for tickets...")
+	err = ticketScanner.Start(ctx)
+	stop()
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("error running scanner: %v", err)
+	}
+
+	log.Println("Stopped scanning for tickets")
+	err = stateStore.Close()
 	if err != nil {
-		log.Fatalf("error running server: %v", err)
+		log.Fatalf("state store error: %v", err)
 	}
 }
 
@@ -101,22 +136,88 @@ func ticketScannerConfigFromUserConfig(conf config.Config) (scanner.TicketScanne
 	}
 
 	// Create notification clients
-	notificationClients, err := notification.GetNotificationClients(conf.Notification)
+	notificationClients, err := notification.GetNotificationClients(conf.Notifiers())
 	if err != nil {
 		return scanner.TicketScannerConfig{}, fmt.Errorf("failed to create notification clients: %w", err)
 	}
 
+	// Get quiet hours, batch windows and timeouts of notifiers
+	quietHours := map[string]config.QuietHoursConfig{}
+	batchWindows := map[string]time.Duration{}
+	notificationTimeouts := map[string]time.Duration{}
+	for _, notifier := range conf.Notifiers() {
+		if notifier.QuietHours != nil {
+			quietHours[notifier.Name] = *notifier.QuietHours
+		}
+		if notifier.BatchWindow > 0 {
+			batchWindows[notifier.Name] = time.Duration(notifier.BatchWindow)
+		}
+		if notifier.Timeout > 0 {
+			notificationTimeouts[notifier.Name] = time.Duration(notifier.Timeout)
+		}
+	}
+
 	// Get combined ticket listing configs
 	listingConfigs := conf.CombinedTicketListingConfigs()
 
+	// Get scan timings, using defaults if not set
+	scanInterval := time.Duration(conf.ScanInterval)
+	if scanInterval == 0 {
+		scanInterval = defaultScanInterval
+	}
+	scanMaxBackoff := time.Duration(conf.ScanMaxBackoff)
+	if scanMaxBackoff == 0 {
+		scanMaxBackoff = defaultScanMaxBackoff
+	}
+
 	return scanner.TicketScannerConfig{
-		TwicketsClient:      client,
-		NotificationClients: notificationClients,
-		ListingConfigs:      listingConfigs,
-		RefetchTime:         refetchTime,
+		TwicketsClient:       client,
+		Countries:            conf.ScanCountries(),
+		NotificationClients:  notificationClients,
+		ListingConfigs:       listingConfigs,
+		QuietHours:           quietHours,
+		BatchWindows:         batchWindows,
+		NotificationTimeouts: notificationTimeouts,
+		ScanInterval:         scanInterval,
+		ScanJitter:           time.Duration(conf.ScanJitter),
+		ScanMaxBackoff:       scanMaxBackoff,
+		Paused:               conf.Paused,
 	}, nil
 }
 
+// startTelegramCommandHandlers starts handling commands for each telegram bot used by notifiers with commands enabled.
+// Only one handler can poll a bot for commands, so notifiers using the same bot share a handler.
+func startTelegramCommandHandlers(
+	ctx context.Context,
+	conf config.Config,
+	configPath string,
+	stateStore *store.Store,
+) error {
+	telegramConfigs, err := conf.TelegramCommandConfigs()
+	if err != nil {
+		return err
+	}
+
+	for _, telegramConfig := range telegramConfigs {
+		handler, err := notification.NewTelegramCommandHandler(telegramConfig, configPath, stateStore)
+		if err != nil {
+			return fmt.Errorf("failed to setup telegram commands: %w", err)
+		}
+
+		go func() {
+			err := handler.Start(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error(
+					"error handling telegram commands",
+					"error", err,
+				)
+			}
+		}()
+	}
+
+	return nil
+}
+
 func getUserConfigUpdatedCallback(ticketScanner *scanner.TicketScanner) func(config.Config) error {
 	return func(userConfig config.Config) error {
 		// Get scanner config
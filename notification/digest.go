@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/samber/lo"
+)
+
+var (
+	//go:embed templates/digest.tmpl.md
+	digestTemplateFS embed.FS
+	digestTemplate   *template.Template
+)
+
+func init() {
+	digestTemplateText, err := digestTemplateFS.ReadFile("templates/digest.tmpl.md")
+	if err != nil {
+		log.Fatalf("failed to read notification digest template: %v", err)
+	}
+
+	// The default template only uses functions for markup, so its text can be escaped
+	digestTemplate, err = parseFormattedTemplate("digest.tmpl.md", string(digestTemplateText), true)
+	if err != nil {
+		log.Fatalf("failed to parse notification digest template: %v", err)
+	}
+}
+
+// DigestClient is a client that can send a single notification for several ticket listings,
+// e.g. for the notifications held during quiet hours or a batch window.
+// Notifications are sent for each ticket listing by clients that cannot send digests.
+//
+// The ticket listing config is that of the listings if they are all for the same event, otherwise it is empty.
+type DigestClient interface {
+	Client
+	SendDigestNotification(context.Context, []twigots.TicketListing, config.TicketListingConfig) error
+}
+
+type DigestTemplateData struct {
+	NumListings int
+
+	// Ticket listings in the digest, in the order they were listed.
+	// The event name and buy link of each are always set.
+	Listings []MessageTemplateData
+
+	// Ticket listings in the digest grouped by event, in the order the events were first listed
+	Events []DigestEventTemplateData
+}
+
+type DigestEventTemplateData struct {
+	Event string
+
+	// Ticket listings for the event, from cheapest to most expensive ticket price
+	Listings []MessageTemplateData
+}
+
+// RenderDigest renders a digest of ticket listings in a format.
+// If the template is nil, the default is used.
+func RenderDigest(tickets []twigots.TicketListing, tmpl *template.Template, format Format) (string, error) {
+	if tmpl == nil {
+		tmpl = digestTemplate
+	}
+
+	digest, err := executeFormattedTemplate(tmpl, format, newDigestTemplateData(tickets))
+	if err != nil {
+		return "", fmt.Errorf("failed to render notification digest template: %w", err)
+	}
+
+	return strings.TrimSpace(digest), nil
+}
+
+// RenderDigestTitle renders the title of a digest of ticket listings.
+// If all the listings are for the same event, the event name is included.
+func RenderDigestTitle(tickets []twigots.TicketListing) string {
+	eventNames := lo.Uniq(lo.Map(tickets, func(ticket twigots.TicketListing, _ int) string {
+		return ticket.Event.Name
+	}))
+	if len(eventNames) == 1 {
+		return fmt.Sprintf("%s: %d new ticket listing(s)", eventNames[0], len(tickets))
+	}
+	return fmt.Sprintf("%d new ticket listing(s)", len(tickets))
+}
+
+func newDigestTemplateData(tickets []twigots.TicketListing) DigestTemplateData {
+	templateData := DigestTemplateData{
+		NumListings: len(tickets),
+		Listings:    lo.Map(tickets, newDigestListingTemplateData),
+	}
+
+	ticketsByEvent := lo.GroupBy(tickets, func(ticket twigots.TicketListing) string { return ticket.Event.Name })
+	for _, ticket := range tickets {
+		eventTickets, ok := ticketsByEvent[ticket.Event.Name]
+		if !ok {
+			continue // Event has already been added
+		}
+		delete(ticketsByEvent, ticket.Event.Name)
+
+		slices.SortStableFunc(eventTickets, func(a, b twigots.TicketListing) int {
+			return cmp.Compare(a.TicketPriceInclFee().Amount, b.TicketPriceInclFee().Amount)
+		})
+
+		templateData.Events = append(templateData.Events, DigestEventTemplateData{
+			Event:    ticket.Event.Name,
+			Listings: lo.Map(eventTickets, newDigestListingTemplateData),
+		})
+	}
+
+	return templateData
+}
+
+// newDigestListingTemplateData gets the template data of a listing in a digest, which always includes the header and footer
+func newDigestListingTemplateData(ticket twigots.TicketListing, _ int) MessageTemplateData {
+	listingData := newMessageTemplateData(ticket)
+	listingData.Event = ticket.Event.Name
+	listingData.Link = ticket.URL()
+	return listingData
+}
@@ -0,0 +1,242 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+// Maximum number of times to retry sending a message after being rate limited
+const discordMaxRateLimitRetries = 3
+
+// Maximum length of the description of an embed
+const discordMaxDescriptionLength = 4096
+
+// Embed colours, from worst to best discount
+const (
+	discordColourNoDiscount    = 0x95A5A6 // Grey
+	discordColourSmallDiscount = 0xE67E22 // Orange
+	discordColourDiscount      = 0xF1C40F // Yellow
+	discordColourBigDiscount   = 0x2ECC71 // Green
+)
+
+type DiscordClient struct {
+	webhookUrl string
+	username   string
+
+	templates Templates
+	client    *http.Client
+}
+
+var _ DigestClient = DiscordClient{}
+
+// discordMessage is a message sent to a discord webhook
+// See https://discord.com/developers/docs/resources/webhook#execute-webhook
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Url         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   *time.Time          `json:"timestamp,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordRateLimitResponse is the body of a response when rate limited
+// See https://discord.com/developers/docs/topics/rate-limits#exceeding-a-rate-limit
+type discordRateLimitResponse struct {
+	RetryAfter float64 `json:"retry_after"` // Seconds
+}
+
+func (c DiscordClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	embed, err := c.renderEmbed(ticket)
+	if err != nil {
+		return err
+	}
+
+	return c.sendEmbed(ctx, embed)
+}
+
+func (c DiscordClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	description, err := RenderDigest(tickets, c.templates.Digest, FormatMarkdown)
+	if err != nil {
+		return err
+	}
+
+	// Colour the digest by its best discount
+	var bestDiscountPercent float64
+	for _, ticket := range tickets {
+		bestDiscountPercent = max(bestDiscountPercent, ticket.Discount()*100)
+	}
+
+	return c.sendEmbed(ctx, discordEmbed{
+		Title:       RenderDigestTitle(tickets),
+		Description: truncate(description, discordMaxDescriptionLength),
+		Color:       discordDiscountColour(bestDiscountPercent),
+	})
+}
+
+// sendEmbed sends a message with an embed, retrying if rate limited
+func (c DiscordClient) sendEmbed(ctx context.Context, embed discordEmbed) error {
+	body, err := json.Marshal(discordMessage{
+		Username: c.username,
+		Embeds:   []discordEmbed{embed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord message: %w", err)
+	}
+
+	for retries := 0; ; retries++ {
+		retryAfter, err := c.send(ctx, body)
+		if err != nil {
+			return err
+		}
+		if retryAfter == 0 {
+			return nil
+		}
+
+		if retries == discordMaxRateLimitRetries {
+			return fmt.Errorf("discord rate limit exceeded after %d retries", retries)
+		}
+
+		// Wait until the rate limit resets
+		select {
+		case <-time.After(retryAfter):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// send a message to the webhook.
+// If rate limited, the time to wait before retrying is returned.
+func (c DiscordClient) send(ctx context.Context, body []byte) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create discord request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read discord response: %w", err)
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		var rateLimit discordRateLimitResponse
+		err := json.Unmarshal(responseBody, &rateLimit)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse discord rate limit response: %w", err)
+		}
+
+		// Always wait a little, so a missing retry after does not look like success
+		retryAfter := time.Duration(rateLimit.RetryAfter * float64(time.Second))
+		return max(retryAfter, time.Millisecond), nil
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return 0, fmt.Errorf("discord returned status %s: %s", response.Status, string(responseBody))
+	}
+
+	return 0, nil
+}
+
+func (c DiscordClient) renderEmbed(ticket twigots.TicketListing) (discordEmbed, error) {
+	title, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return discordEmbed{}, err
+	}
+
+	// Only add a description if there is a custom message template,
+	// as the default message has the same details as the fields
+	var description string
+	if c.templates.Message != nil {
+		description, err = RenderMessage(ticket, WithTemplate(c.templates.Message))
+		if err != nil {
+			return discordEmbed{}, err
+		}
+	}
+
+	data := newMessageTemplateData(ticket)
+
+	price := data.TotalTicketPrice + " per ticket"
+	if data.NumTickets > 1 {
+		price += fmt.Sprintf(" (%s total)", data.TotalPrice)
+	}
+	if data.AcceptsOffers {
+		price += "\nOffers Accepted"
+	}
+
+	embed := discordEmbed{
+		Title:       title,
+		Description: description,
+		Url:         ticket.URL(),
+		Color:       discordDiscountColour(data.DiscountPercent),
+		Fields: []discordEmbedField{
+			{Name: "Date", Value: fmt.Sprintf("%s %s", data.Date, data.Time), Inline: true},
+			{Name: "Venue", Value: fmt.Sprintf("%s, %s", data.Venue, data.Location), Inline: true},
+			{Name: "Tickets", Value: fmt.Sprintf("%d - %s", data.NumTickets, data.TicketType), Inline: true},
+			{Name: "Price", Value: price, Inline: true},
+			{Name: "Discount", Value: data.Discount, Inline: true},
+		},
+	}
+	if !data.ListedAt.IsZero() {
+		embed.Timestamp = &data.ListedAt
+	}
+
+	return embed, nil
+}
+
+// discordDiscountColour gets the colour of an embed for a discount percentage
+func discordDiscountColour(discountPercent float64) int {
+	switch {
+	case discountPercent >= 50:
+		return discordColourBigDiscount
+	case discountPercent >= 20:
+		return discordColourDiscount
+	case discountPercent > 0:
+		return discordColourSmallDiscount
+	default:
+		return discordColourNoDiscount
+	}
+}
+
+func NewDiscordClient(conf config.DiscordConfig) (DiscordClient, error) {
+	return DiscordClient{
+		webhookUrl: conf.WebhookUrl,
+		username:   conf.Username,
+
+		client: http.DefaultClient,
+	}, nil
+}
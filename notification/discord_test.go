@@ -0,0 +1,110 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordSendTicketMessage(t *testing.T) {
+	// Rate limit the first request
+	var numRequests atomic.Int32
+	bodies := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies <- body
+
+		if numRequests.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.01, "global": false}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := notification.NewDiscordClient(config.DiscordConfig{WebhookUrl: server.URL})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, numRequests.Load())
+
+	var message struct {
+		Embeds []struct {
+			Title  string `json:"title"`
+			Url    string `json:"url"`
+			Color  int    `json:"color"`
+			Fields []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"embeds"`
+	}
+	<-bodies
+	err = json.Unmarshal(<-bodies, &message)
+	require.NoError(t, err)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	require.Equal(t, "Test Event", embed.Title)
+	require.Equal(t, ticket.URL(), embed.Url)
+	require.Equal(t, 0xF1C40F, embed.Color) // 25% discount
+
+	fields := make(map[string]string, len(embed.Fields))
+	for _, field := range embed.Fields {
+		fields[field.Name] = field.Value
+	}
+	require.Equal(t, "Test Venue, Test Location", fields["Venue"])
+	require.Equal(t, "2 - Standing", fields["Tickets"])
+	require.Equal(t, "25.00%", fields["Discount"])
+}
+
+func TestDiscordSendDigest(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies <- body
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := notification.NewDiscordClient(config.DiscordConfig{WebhookUrl: server.URL})
+	require.NoError(t, err)
+
+	err = client.SendDigestNotification(context.Background(), testDigestTickets(), config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	var message struct {
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Color       int    `json:"color"`
+		} `json:"embeds"`
+	}
+	err = json.Unmarshal(<-bodies, &message)
+	require.NoError(t, err)
+
+	expectedDigest, err := os.ReadFile(test.ProjectDirectoryJoin(t, "test", "data", "message", "digest.md"))
+	require.NoError(t, err)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	require.Equal(t, "3 new ticket listing(s)", embed.Title)
+	require.Equal(t, string(expectedDigest), embed.Description)
+	require.Equal(t, 0x2ECC71, embed.Color) // Best discount is 50%
+}
@@ -0,0 +1,376 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+var (
+	//go:embed templates/email.tmpl.html templates/email_digest.tmpl.html
+	emailTemplateFS     embed.FS
+	emailTemplate       *template.Template
+	emailDigestTemplate *template.Template
+)
+
+func init() {
+	var err error
+	emailTemplate, err = template.ParseFS(emailTemplateFS, "templates/email.tmpl.html")
+	if err != nil {
+		log.Fatalf("failed to read email template: %v", err)
+	}
+
+	emailDigestTemplate, err = template.ParseFS(emailTemplateFS, "templates/email_digest.tmpl.html")
+	if err != nil {
+		log.Fatalf("failed to read email digest template: %v", err)
+	}
+}
+
+type EmailClient struct {
+	host     string
+	port     int
+	security config.EmailSecurity
+	username string
+	password string
+	from     *mail.Address
+	to       []*mail.Address
+
+	templates Templates
+	tlsConfig *tls.Config
+}
+
+var _ DigestClient = EmailClient{}
+
+// emailTemplateData is the data the html email template is rendered with
+type emailTemplateData struct {
+	MessageTemplateData
+	Title         string
+	CustomMessage string
+}
+
+// emailDigestTemplateData is the data the html email digest template is rendered with
+type emailDigestTemplateData struct {
+	DigestTemplateData
+	Title        string
+	CustomDigest string
+}
+
+func (c EmailClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	email, err := c.renderEmail(ticket)
+	if err != nil {
+		return err
+	}
+
+	return c.sendEmail(ctx, email)
+}
+
+func (c EmailClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	email, err := c.renderDigestEmail(tickets)
+	if err != nil {
+		return err
+	}
+
+	return c.sendEmail(ctx, email)
+}
+
+// sendEmail sends an email, returning the context error if the context is done
+func (c EmailClient) sendEmail(ctx context.Context, email []byte) error {
+	err := c.send(ctx, email)
+	if err != nil {
+		// Closing the connection when the context is done causes confusing errors,
+		// so return the context error instead
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// send an email using the smtp server
+func (c EmailClient) send(ctx context.Context, email []byte) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	defer conn.Close()
+
+	// The smtp client does not support contexts, so stop it by closing the connection
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if c.security == config.EmailSecurityStarttls {
+		supported, _ := client.Extension("STARTTLS")
+		if !supported {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+
+		err = client.StartTLS(c.tlsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if c.username != "" {
+		err = client.Auth(smtp.PlainAuth("", c.username, c.password, c.host))
+		if err != nil {
+			return fmt.Errorf("failed to authenticate with smtp server: %w", err)
+		}
+	}
+
+	err = client.Mail(c.from.Address)
+	if err != nil {
+		return fmt.Errorf("smtp server rejected from address: %w", err)
+	}
+
+	for _, to := range c.to {
+		err = client.Rcpt(to.Address)
+		if err != nil {
+			return fmt.Errorf("smtp server rejected to address '%s': %w", to.Address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start sending email: %w", err)
+	}
+
+	_, err = writer.Write(email)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the smtp server, using tls if the server uses implicit tls
+func (c EmailClient) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+
+	if c.security == config.EmailSecurityTls {
+		dialer := &tls.Dialer{Config: c.tlsConfig}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// renderEmail renders a multipart email with plain text and html bodies
+func (c EmailClient) renderEmail(ticket twigots.TicketListing) ([]byte, error) {
+	title, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	plainBody, err := RenderMessage(
+		ticket,
+		WithHeader(),
+		WithFooter(),
+		WithTemplate(c.templates.Message),
+		WithFormat(FormatPlain),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	htmlBody, err := c.renderHtmlBody(ticket, title)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.buildEmail(title, plainBody, htmlBody)
+}
+
+// renderDigestEmail renders a multipart email with plain text and html bodies for a digest of ticket listings
+func (c EmailClient) renderDigestEmail(tickets []twigots.TicketListing) ([]byte, error) {
+	title := RenderDigestTitle(tickets)
+
+	plainBody, err := RenderDigest(tickets, c.templates.Digest, FormatPlain)
+	if err != nil {
+		return nil, err
+	}
+
+	templateData := emailDigestTemplateData{
+		DigestTemplateData: newDigestTemplateData(tickets),
+		Title:              title,
+	}
+
+	// Only add the digest if there is a custom digest template,
+	// otherwise the listings are shown in tables
+	if c.templates.Digest != nil {
+		templateData.CustomDigest = plainBody
+	}
+
+	var htmlBody bytes.Buffer
+	err = emailDigestTemplate.Execute(&htmlBody, templateData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email digest template: %w", err)
+	}
+
+	return c.buildEmail(title, plainBody, htmlBody.String())
+}
+
+// buildEmail builds a multipart email with plain text and html bodies
+func (c EmailClient) buildEmail(title, plainBody, htmlBody string) ([]byte, error) {
+	var body bytes.Buffer
+	bodyWriter := multipart.NewWriter(&body)
+
+	// Parts are in order of preference, so the html body must be last
+	err := writeEmailPart(bodyWriter, "text/plain; charset=utf-8", plainBody)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeEmailPart(bodyWriter, "text/html; charset=utf-8", htmlBody)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bodyWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write email body: %w", err)
+	}
+
+	to := make([]string, 0, len(c.to))
+	for _, address := range c.to {
+		to = append(to, address.String())
+	}
+
+	var email bytes.Buffer
+	email.WriteString("From: " + c.from.String() + "\r\n")
+	email.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	email.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", title) + "\r\n")
+	email.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: multipart/alternative; boundary=" + bodyWriter.Boundary() + "\r\n")
+	email.WriteString("\r\n")
+	email.Write(body.Bytes())
+
+	return email.Bytes(), nil
+}
+
+func (c EmailClient) renderHtmlBody(ticket twigots.TicketListing, title string) (string, error) {
+	templateData := emailTemplateData{
+		MessageTemplateData: newMessageTemplateData(ticket),
+		Title:               title,
+	}
+	templateData.Event = ticket.Event.Name
+	templateData.Link = ticket.URL()
+
+	// Only add the message if there is a custom message template,
+	// as the default message has the same details as the table
+	if c.templates.Message != nil {
+		customMessage, err := RenderMessage(ticket, WithTemplate(c.templates.Message), WithFormat(FormatPlain))
+		if err != nil {
+			return "", err
+		}
+		templateData.CustomMessage = customMessage
+	}
+
+	var buffer bytes.Buffer
+	err := emailTemplate.Execute(&buffer, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+// writeEmailPart writes a quoted-printable encoded part to a multipart email body
+func writeEmailPart(writer *multipart.Writer, contentType, content string) error {
+	partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email part: %w", err)
+	}
+
+	encoder := quotedprintable.NewWriter(partWriter)
+	_, err = encoder.Write([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to write email part: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write email part: %w", err)
+	}
+
+	return nil
+}
+
+func NewEmailClient(conf config.EmailConfig) (EmailClient, error) {
+	from, err := mail.ParseAddress(conf.From)
+	if err != nil {
+		return EmailClient{}, fmt.Errorf("from address is not valid: %w", err)
+	}
+
+	to := make([]*mail.Address, 0, len(conf.To))
+	for _, toAddress := range conf.To {
+		address, err := mail.ParseAddress(toAddress)
+		if err != nil {
+			return EmailClient{}, fmt.Errorf("to address '%s' is not valid: %w", toAddress, err)
+		}
+		to = append(to, address)
+	}
+
+	security := conf.Security
+	if security == (config.EmailSecurity{}) {
+		security = config.EmailSecurityStarttls
+	}
+
+	return EmailClient{
+		host:     conf.Host,
+		port:     conf.Port,
+		security: security,
+		username: conf.Username,
+		password: conf.Password,
+		from:     from,
+		to:       to,
+
+		tlsConfig: &tls.Config{
+			ServerName: conf.Host,
+			MinVersion: tls.VersionTLS12,
+		},
+	}, nil
+}
@@ -0,0 +1,238 @@
+package notification_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/stretchr/testify/require"
+)
+
+type smtpEmail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// newSmtpServer starts a minimal smtp server that records the email it receives.
+// If rejectTo is set, the server rejects that recipient.
+func newSmtpServer(t *testing.T, rejectTo string) (host string, port int, emails <-chan smtpEmail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan smtpEmail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		_ = text.PrintfLine("220 localhost ESMTP")
+
+		var email smtpEmail
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO":
+				_ = text.PrintfLine("250-localhost")
+				_ = text.PrintfLine("250 8BITMIME")
+			case "MAIL":
+				email.from = smtpCommandAddress(line)
+				_ = text.PrintfLine("250 OK")
+			case "RCPT":
+				to := smtpCommandAddress(line)
+				if to == rejectTo {
+					_ = text.PrintfLine("550 No such user")
+					continue
+				}
+				email.to = append(email.to, to)
+				_ = text.PrintfLine("250 OK")
+			case "DATA":
+				_ = text.PrintfLine("354 Send data")
+				email.data, err = text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received <- email
+				_ = text.PrintfLine("250 OK")
+			case "QUIT":
+				_ = text.PrintfLine("221 Bye")
+				return
+			default:
+				_ = text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), address.Port, received
+}
+
+// smtpCommandAddress gets the address from a MAIL FROM:<address> or RCPT TO:<address> command
+func smtpCommandAddress(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.Index(line, ">")
+	if start == -1 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func TestEmailSendTicketMessage(t *testing.T) {
+	host, port, emails := newSmtpServer(t, "")
+
+	client, err := notification.NewEmailClient(config.EmailConfig{
+		Host:     host,
+		Port:     port,
+		Security: config.EmailSecurityNone,
+		From:     "Twitchets <twitchets@example.com>",
+		To:       []string{"one@example.com", "two@example.com"},
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	ticket.Event.Venue.Name = "Bar & Grill <Upstairs>"
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	email := <-emails
+	require.Equal(t, "twitchets@example.com", email.from)
+	require.Equal(t, []string{"one@example.com", "two@example.com"}, email.to)
+
+	message, err := mail.ReadMessage(bytes.NewReader(email.data))
+	require.NoError(t, err)
+	require.Equal(t, "Test Event", message.Header.Get("Subject"))
+	require.Equal(t, `"Twitchets" <twitchets@example.com>`, message.Header.Get("From"))
+	require.Equal(t, "<one@example.com>, <two@example.com>", message.Header.Get("To"))
+
+	parts := readEmailParts(t, message)
+	require.Len(t, parts, 2)
+
+	plainBody := parts["text/plain; charset=utf-8"]
+	require.True(t, strings.HasPrefix(plainBody, "Test Event\n"))
+	require.Contains(t, plainBody, "Bar & Grill <Upstairs>, Test Location")
+	require.Contains(t, plainBody, "Buy Link: "+ticket.URL())
+
+	htmlBody := parts["text/html; charset=utf-8"]
+	require.Contains(t, htmlBody, "<h2>Test Event</h2>")
+	require.Contains(t, htmlBody, "Bar &amp; Grill &lt;Upstairs&gt;, Test Location")
+	require.Contains(t, htmlBody, `<a href="`+ticket.URL()+`">`)
+}
+
+func TestEmailSendDigest(t *testing.T) {
+	host, port, emails := newSmtpServer(t, "")
+
+	client, err := notification.NewEmailClient(config.EmailConfig{
+		Host:     host,
+		Port:     port,
+		Security: config.EmailSecurityNone,
+		From:     "twitchets@example.com",
+		To:       []string{"one@example.com"},
+	})
+	require.NoError(t, err)
+
+	tickets := testDigestTickets()
+	tickets[1].Event.Name = "Bar & Grill <Upstairs>"
+	err = client.SendDigestNotification(context.Background(), tickets, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader((<-emails).data))
+	require.NoError(t, err)
+	require.Equal(t, "3 new ticket listing(s)", message.Header.Get("Subject"))
+
+	parts := readEmailParts(t, message)
+	require.Len(t, parts, 2)
+
+	plainBody := parts["text/plain; charset=utf-8"]
+	require.True(t, strings.HasPrefix(plainBody, "Test Event\n£1.00 | 2 ticket(s) | 50.00% off"))
+	require.Contains(t, plainBody, "Bar & Grill <Upstairs>\n")
+
+	htmlBody := parts["text/html; charset=utf-8"]
+	require.Contains(t, htmlBody, "<h2>3 new ticket listing(s)</h2>")
+	require.Contains(t, htmlBody, "<h3>Test Event</h3>")
+	require.Contains(t, htmlBody, "<h3>Bar &amp; Grill &lt;Upstairs&gt;</h3>")
+	require.Contains(t, htmlBody, `<a href="https://www.twickets.live/app/block/cheaper,2">`)
+}
+
+// readEmailParts reads the parts of a multipart email, keyed by content type.
+// Parts are decoded from quoted-printable by the reader.
+func readEmailParts(t *testing.T, message *mail.Message) map[string]string {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts[part.Header.Get("Content-Type")] = string(content)
+	}
+
+	return parts
+}
+
+func TestEmailSendTicketMessageRejectedRecipient(t *testing.T) {
+	host, port, _ := newSmtpServer(t, "unknown@example.com")
+
+	client, err := notification.NewEmailClient(config.EmailConfig{
+		Host:     host,
+		Port:     port,
+		Security: config.EmailSecurityNone,
+		From:     "twitchets@example.com",
+		To:       []string{"unknown@example.com"},
+	})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "550")
+}
+
+func TestEmailSendTicketMessageStartTlsNotSupported(t *testing.T) {
+	host, port, _ := newSmtpServer(t, "")
+
+	// Security defaults to STARTTLS, which the server does not support
+	client, err := notification.NewEmailClient(config.EmailConfig{
+		Host: host,
+		Port: port,
+		From: "twitchets@example.com",
+		To:   []string{"one@example.com"},
+	})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "STARTTLS")
+}
+
+func TestNewEmailClientInvalidAddress(t *testing.T) {
+	_, err := notification.NewEmailClient(config.EmailConfig{
+		Host: "localhost",
+		Port: 25,
+		From: "not an address",
+		To:   []string{"one@example.com"},
+	})
+	require.Error(t, err)
+}
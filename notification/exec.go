@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+const (
+	execDefaultTimeout = 30 * time.Second
+
+	// Time to wait for output to be closed after a command is stopped.
+	// Without this, commands that start processes that outlive them could block forever.
+	execWaitDelay = 5 * time.Second
+
+	// Maximum number of characters of stderr to include in errors
+	execMaxErrorOutputLength = 500
+)
+
+type ExecClient struct {
+	command string
+	args    []string
+	timeout time.Duration
+
+	templates Templates
+}
+
+var _ Client = ExecClient{}
+
+func (c ExecClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) error {
+	payload, err := newWebhookPayload(ticket, listingConfig, c.templates)
+	if err != nil {
+		return err
+	}
+
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exec payload: %w", err)
+	}
+
+	// The command is also stopped if the notification times out first
+	timeout := c.timeout
+	deadline, ok := ctx.Deadline()
+	if ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	command := exec.CommandContext(ctx, c.command, c.args...)
+	command.Stdin = bytes.NewReader(stdin)
+	command.Stderr = &stderr
+	command.Env = append(os.Environ(), execEnvironment(payload)...)
+	command.WaitDelay = execWaitDelay
+
+	err = command.Run()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("exec command timed out after %s", timeout.Round(time.Millisecond))
+		}
+
+		output := truncate(strings.TrimSpace(stderr.String()), execMaxErrorOutputLength)
+		if output == "" {
+			return fmt.Errorf("exec command failed: %w", err)
+		}
+		return fmt.Errorf("exec command failed: %w: %s", err, output)
+	}
+
+	return nil
+}
+
+// execEnvironment gets the environment variables to set for a command
+func execEnvironment(payload WebhookPayload) []string {
+	data := newMessageTemplateData(payload.Listing)
+	environment := map[string]string{
+		"TWITCHETS_TITLE":            payload.Title,
+		"TWITCHETS_MESSAGE":          payload.Message,
+		"TWITCHETS_LINK":             payload.Link,
+		"TWITCHETS_LISTING_ID":       payload.Listing.Id,
+		"TWITCHETS_EVENT":            payload.ListingConfig.Event,
+		"TWITCHETS_LISTING_EVENT":    payload.Listing.Event.Name,
+		"TWITCHETS_VENUE":            data.Venue,
+		"TWITCHETS_LOCATION":         data.Location,
+		"TWITCHETS_DATE":             data.Date,
+		"TWITCHETS_TIME":             data.Time,
+		"TWITCHETS_TICKET_TYPE":      data.TicketType,
+		"TWITCHETS_NUM_TICKETS":      strconv.Itoa(data.NumTickets),
+		"TWITCHETS_TICKET_PRICE":     data.TotalTicketPrice,
+		"TWITCHETS_TOTAL_PRICE":      data.TotalPrice,
+		"TWITCHETS_DISCOUNT":         data.Discount,
+		"TWITCHETS_DISCOUNT_PERCENT": strconv.FormatFloat(data.DiscountPercent, 'f', 2, 64),
+		"TWITCHETS_ACCEPTS_OFFERS":   strconv.FormatBool(data.AcceptsOffers),
+	}
+
+	variables := make([]string, 0, len(environment))
+	for name, value := range environment {
+		variables = append(variables, name+"="+value)
+	}
+	return variables
+}
+
+func NewExecClient(conf config.ExecConfig) (ExecClient, error) {
+	if conf.Command == "" {
+		return ExecClient{}, errors.New("command must be set")
+	}
+
+	timeout := time.Duration(conf.Timeout)
+	if timeout <= 0 {
+		timeout = execDefaultTimeout
+	}
+
+	return ExecClient{
+		command: conf.Command,
+		args:    conf.Args,
+		timeout: timeout,
+	}, nil
+}
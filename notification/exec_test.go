@@ -0,0 +1,98 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecSendTicketMessage(t *testing.T) {
+	outputDir := t.TempDir()
+	stdinPath := filepath.Join(outputDir, "stdin.json")
+	eventPath := filepath.Join(outputDir, "event.txt")
+
+	client, err := notification.NewExecClient(config.ExecConfig{
+		Command: "sh",
+		Args: []string{
+			"-c", `cat > "$1" && printf '%s' "$TWITCHETS_EVENT|$TWITCHETS_NUM_TICKETS|$TWITCHETS_LINK" > "$2"`,
+			"sh", stdinPath, eventPath,
+		},
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	listingConfig := config.TicketListingConfig{Event: "Wanted Event"}
+	err = client.SendTicketNotification(context.Background(), ticket, listingConfig)
+	require.NoError(t, err)
+
+	environment, err := os.ReadFile(eventPath)
+	require.NoError(t, err)
+	require.Equal(t, "Wanted Event|2|"+ticket.URL(), string(environment))
+
+	stdin, err := os.ReadFile(stdinPath)
+	require.NoError(t, err)
+
+	// Listings cannot be unmarshalled after being marshalled, so only check the listing id
+	var payload struct {
+		Version       int                        `json:"version"`
+		Title         string                     `json:"title"`
+		Listing       map[string]any             `json:"listing"`
+		ListingConfig config.TicketListingConfig `json:"listingConfig"`
+	}
+	err = json.Unmarshal(stdin, &payload)
+	require.NoError(t, err)
+	require.Equal(t, notification.WebhookPayloadVersion, payload.Version)
+	require.Equal(t, "Test Event", payload.Title)
+	require.Equal(t, ticket.Id, payload.Listing["blockId"])
+	require.Equal(t, listingConfig, payload.ListingConfig)
+}
+
+func TestExecSendTicketMessageNonZeroExit(t *testing.T) {
+	client, err := notification.NewExecClient(config.ExecConfig{
+		Command: "sh",
+		Args:    []string{"-c", "echo 'something went wrong' >&2; exit 3"},
+	})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "exit status 3")
+	require.ErrorContains(t, err, "something went wrong")
+}
+
+func TestExecSendTicketMessageTimeout(t *testing.T) {
+	client, err := notification.NewExecClient(config.ExecConfig{
+		Command: "sleep",
+		Args:    []string{"10"},
+		Timeout: config.Duration(100 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = client.SendTicketNotification(context.Background(), testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "timed out after 100ms")
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExecSendTicketMessageNotificationTimeout(t *testing.T) {
+	client, err := notification.NewExecClient(config.ExecConfig{
+		Command: "sleep",
+		Args:    []string{"10"},
+		Timeout: config.Duration(5 * time.Second),
+	})
+	require.NoError(t, err)
+
+	// Error should report the notification deadline, as it is sooner than the exec timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = client.SendTicketNotification(ctx, testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "timed out after")
+	require.NotContains(t, err.Error(), "5s")
+}
@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Format is the formatting of a rendered notification message,
+// so markup and escaping match how the message is displayed.
+type Format int
+
+const (
+	// FormatMarkdown is CommonMark markdown, as displayed by ntfy and gotify
+	FormatMarkdown Format = iota
+	// FormatPlain is plain text, with no markup
+	FormatPlain
+	// FormatTelegramHTML is the HTML supported by telegram
+	// See https://core.telegram.org/bots/api#html-style
+	FormatTelegramHTML
+	// FormatTelegramMarkdownV2 is the MarkdownV2 supported by telegram
+	// See https://core.telegram.org/bots/api#markdownv2-style
+	FormatTelegramMarkdownV2
+	// FormatSlackMrkdwn is the mrkdwn supported by slack
+	// See https://api.slack.com/reference/surfaces/formatting
+	FormatSlackMrkdwn
+)
+
+var (
+	markdownEscaper = newBackslashEscaper("\\`*_[]<>#|~")
+
+	telegramHTMLEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+	telegramMarkdownV2Escaper    = newBackslashEscaper("\\_*[]()~`>#+-=|{}.!")
+	telegramMarkdownV2UrlEscaper = newBackslashEscaper("\\)")
+
+	// Slack only needs the characters used for links and mentions escaping
+	// See https://api.slack.com/reference/surfaces/formatting#escaping
+	slackMrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// formattedText is text that has already been formatted, so should not be escaped again
+type formattedText string
+
+// Escape text, so it is displayed as is rather than as markup
+func (f Format) Escape(text string) string {
+	switch f {
+	case FormatMarkdown:
+		return markdownEscaper.Replace(text)
+	case FormatTelegramHTML:
+		return telegramHTMLEscaper.Replace(text)
+	case FormatTelegramMarkdownV2:
+		return telegramMarkdownV2Escaper.Replace(text)
+	case FormatSlackMrkdwn:
+		return slackMrkdwnEscaper.Replace(text)
+	default:
+		return text
+	}
+}
+
+// Bold makes formatted text bold
+func (f Format) Bold(text string) string {
+	switch f {
+	case FormatMarkdown, FormatTelegramMarkdownV2, FormatSlackMrkdwn:
+		return "*" + text + "*"
+	case FormatTelegramHTML:
+		return "<b>" + text + "</b>"
+	default:
+		return text
+	}
+}
+
+// Link makes formatted text a link to a url
+func (f Format) Link(text, url string) string {
+	switch f {
+	case FormatMarkdown:
+		return fmt.Sprintf("[%s](%s)", text, url)
+	case FormatTelegramHTML:
+		return fmt.Sprintf(`<a href="%s">%s</a>`, telegramHTMLEscaper.Replace(url), text)
+	case FormatTelegramMarkdownV2:
+		return fmt.Sprintf("[%s](%s)", text, telegramMarkdownV2UrlEscaper.Replace(url))
+	case FormatSlackMrkdwn:
+		return fmt.Sprintf("<%s|%s>", slackMrkdwnEscaper.Replace(url), text)
+	default:
+		return fmt.Sprintf("%s: %s", text, url)
+	}
+}
+
+// templateFuncs gets the functions templates use to format text.
+// Values that are not already formatted are escaped.
+func (f Format) templateFuncs() template.FuncMap {
+	escape := func(value any) formattedText {
+		if text, ok := value.(formattedText); ok {
+			return text
+		}
+		return formattedText(f.Escape(fmt.Sprint(value)))
+	}
+
+	return template.FuncMap{
+		"escape": escape,
+		"bold": func(value any) formattedText {
+			return formattedText(f.Bold(string(escape(value))))
+		},
+		"link": func(text any, url string) formattedText {
+			return formattedText(f.Link(string(escape(text)), url))
+		},
+	}
+}
+
+// parseFormattedTemplate parses a template that escapes the values it outputs for the format it is rendered with.
+// If escapeText is true, the text of the template is also escaped, so it must not contain any markup.
+// Markup can instead be added using the bold and link functions.
+func parseFormattedTemplate(name, text string, escapeText bool) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(FormatMarkdown.templateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, definedTemplate := range tmpl.Templates() {
+		if definedTemplate.Tree != nil {
+			escapeTemplateNode(definedTemplate.Tree, definedTemplate.Tree.Root, escapeText)
+		}
+	}
+
+	return tmpl, nil
+}
+
+// executeFormattedTemplate executes a template parsed by parseFormattedTemplate, escaping for a format
+func executeFormattedTemplate(tmpl *template.Template, format Format, data any) (string, error) {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	err = tmpl.Funcs(format.templateFuncs()).Execute(&builder, data)
+	if err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// escapeTemplateNode escapes the values output by a template node and its children,
+// by piping them to the escape function. This is similar to how html/template escapes values.
+func escapeTemplateNode(tree *parse.Tree, node parse.Node, escapeText bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for idx, child := range node.Nodes {
+			// The format is not known until the template is executed,
+			// so text is escaped by replacing it with an action that outputs it using the escape function
+			if textNode, ok := child.(*parse.TextNode); ok && escapeText {
+				node.Nodes[idx] = escapedTextAction(tree, textNode)
+				continue
+			}
+			escapeTemplateNode(tree, child, escapeText)
+		}
+
+	case *parse.ActionNode:
+		// Actions that declare variables do not output anything
+		if len(node.Pipe.Decl) != 0 {
+			return
+		}
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("escape").SetTree(tree).SetPos(node.Pos)},
+		})
+
+	case *parse.IfNode:
+		escapeTemplateNode(tree, node.List, escapeText)
+		escapeTemplateNode(tree, node.ElseList, escapeText)
+	case *parse.RangeNode:
+		escapeTemplateNode(tree, node.List, escapeText)
+		escapeTemplateNode(tree, node.ElseList, escapeText)
+	case *parse.WithNode:
+		escapeTemplateNode(tree, node.List, escapeText)
+		escapeTemplateNode(tree, node.ElseList, escapeText)
+	}
+}
+
+// escapedTextAction creates an action that outputs the text of a text node using the escape function
+func escapedTextAction(tree *parse.Tree, textNode *parse.TextNode) *parse.ActionNode {
+	text := string(textNode.Text)
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      textNode.Pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      textNode.Pos,
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      textNode.Pos,
+				Args: []parse.Node{
+					parse.NewIdentifier("escape").SetTree(tree).SetPos(textNode.Pos),
+					&parse.StringNode{
+						NodeType: parse.NodeString,
+						Pos:      textNode.Pos,
+						Quoted:   strconv.Quote(text),
+						Text:     text,
+					},
+				},
+			}},
+		},
+	}
+}
+
+// newBackslashEscaper creates a replacer that escapes characters by adding a backslash before them
+func newBackslashEscaper(characters string) *strings.Replacer {
+	replacements := make([]string, 0, 2*len(characters))
+	for _, character := range characters {
+		replacements = append(replacements, string(character), `\`+string(character))
+	}
+	return strings.NewReplacer(replacements...)
+}
@@ -0,0 +1,79 @@
+package notification_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/test"
+	"github.com/stretchr/testify/require"
+)
+
+// testFormatTicket gets a ticket listing with names containing characters used for markup
+func testFormatTicket() twigots.TicketListing {
+	ticket := testNotificationTicket()
+	ticket.Event.Name = "The_Eras *Tour* [Live] (2025)"
+	ticket.Event.Venue.Name = "Bar & Grill <Upstairs>"
+	return ticket
+}
+
+func TestRenderMessageWithFormat(t *testing.T) {
+	tests := []struct {
+		format       notification.Format
+		expectedFile string
+	}{
+		{format: notification.FormatMarkdown, expectedFile: "messageFormatMarkdown.md"},
+		{format: notification.FormatPlain, expectedFile: "messageFormatPlain.txt"},
+		{format: notification.FormatTelegramHTML, expectedFile: "messageFormatTelegramHTML.html"},
+		{format: notification.FormatTelegramMarkdownV2, expectedFile: "messageFormatTelegramMarkdownV2.md"},
+		{format: notification.FormatSlackMrkdwn, expectedFile: "messageFormatSlackMrkdwn.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expectedFile, func(t *testing.T) {
+			expectedMessagePath := test.ProjectDirectoryJoin(t, "test", "data", "message", tt.expectedFile)
+			expectedMessageBytes, err := os.ReadFile(expectedMessagePath)
+			require.NoError(t, err)
+			expectedMessage := string(expectedMessageBytes)
+
+			actualMessage, err := notification.RenderMessage(
+				testFormatTicket(),
+				notification.WithHeader(),
+				notification.WithFooter(),
+				notification.WithFormat(tt.format),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, expectedMessage, actualMessage)
+		})
+	}
+}
+
+func TestRenderMessageWithTemplateAndFormat(t *testing.T) {
+	templates, err := notification.NewTemplates(config.NotifierConfig{
+		Template:      "<i>{{ .Event }}</i> at {{ .Venue }}. {{ link \"Buy\" .Link }}",
+		TitleTemplate: "Tickets for {{ .Venue }}",
+	})
+	require.NoError(t, err)
+
+	// Values should be escaped, but the text of the template should not
+	ticket := testFormatTicket()
+	actualMessage, err := notification.RenderMessage(
+		ticket,
+		notification.WithTemplate(templates.Message),
+		notification.WithFormat(notification.FormatTelegramHTML),
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		`<i>The_Eras *Tour* [Live] (2025)</i> at Bar &amp; Grill &lt;Upstairs&gt;. `+
+			`<a href="https://www.twickets.live/app/block/test,2">Buy</a>`,
+		actualMessage,
+	)
+
+	// Titles should not be escaped
+	actualTitle, err := notification.RenderTitle(ticket, templates.Title)
+	require.NoError(t, err)
+	require.Equal(t, "Tickets for Bar & Grill <Upstairs>", actualTitle)
+}
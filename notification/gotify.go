@@ -1,9 +1,13 @@
 package notification
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
@@ -12,41 +16,104 @@ import (
 	"github.com/gotify/go-api-client/v2/client/message"
 	"github.com/gotify/go-api-client/v2/gotify"
 	"github.com/gotify/go-api-client/v2/models"
+	"github.com/samber/lo"
 )
 
+// Time to wait for gotify to check the token when creating a client
+const gotifyValidateTokenTimeout = 10 * time.Second
+
+// Priority of gotify notifications if not set
+const gotifyDefaultPriority = 5
+
 type GotifyClient struct {
 	url   *url.URL
 	token string
 
-	client *client.GotifyREST
+	priority      int
+	priorityRules []config.PriorityRule
+
+	templates Templates
+	client    *client.GotifyREST
 }
 
-var _ Client = GotifyClient{}
+var _ DigestClient = GotifyClient{}
 
-func (g GotifyClient) SendTicketNotification(ticket twigots.TicketListing) error {
-	notificationMessage, err := RenderMessage(ticket, WithFooter())
+func (g GotifyClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderMessage(
+		ticket,
+		WithFooter(),
+		WithTemplate(g.templates.Message),
+		WithFormat(FormatMarkdown),
+	)
 	if err != nil {
 		return err
 	}
 
-	params := message.NewCreateMessageParams()
-	params.Body = &models.MessageExternal{
-		Title:   ticket.Event.Name,
-		Message: notificationMessage,
-		Extras: map[string]any{
-			"client::display": map[string]any{
-				"contentType": "text/markdown",
-			},
-			"client::notification": map[string]any{
-				"click": map[string]any{
-					"url": ticket.URL(),
-				},
-			},
+	notificationTitle, err := RenderTitle(ticket, g.templates.Title)
+	if err != nil {
+		return err
+	}
+
+	return g.createMessage(
+		ctx,
+		notificationTitle,
+		notificationMessage,
+		config.ListingPriority(ticket, g.priorityRules, g.priority),
+		ticket.URL(),
+	)
+}
+
+func (g GotifyClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderDigest(tickets, g.templates.Digest, FormatMarkdown)
+	if err != nil {
+		return err
+	}
+
+	// Use the highest priority of the listings
+	priority := lo.Max(lo.Map(tickets, func(ticket twigots.TicketListing, _ int) int {
+		return config.ListingPriority(ticket, g.priorityRules, g.priority)
+	}))
+
+	return g.createMessage(ctx, RenderDigestTitle(tickets), notificationMessage, priority, "")
+}
+
+// createMessage creates a markdown message, which opens a url when clicked if it is set
+func (g GotifyClient) createMessage(
+	ctx context.Context,
+	title, notificationMessage string,
+	priority int,
+	clickUrl string,
+) error {
+	extras := map[string]any{
+		"client::display": map[string]any{
+			"contentType": "text/markdown",
 		},
-		Priority: 5,
+	}
+	if clickUrl != "" {
+		extras["client::notification"] = map[string]any{
+			"click": map[string]any{
+				"url": clickUrl,
+			},
+		}
+	}
+
+	params := message.NewCreateMessageParamsWithContext(ctx)
+	params.Body = &models.MessageExternal{
+		Title:    title,
+		Message:  notificationMessage,
+		Extras:   extras,
+		Priority: priority,
 	}
 
-	_, err = g.client.Message.CreateMessage(
+	_, err := g.client.Message.CreateMessage(
 		params,
 		auth.TokenAuth(g.token),
 	)
@@ -63,10 +130,57 @@ func NewGotifyClient(conf config.GotifyConfig) (GotifyClient, error) {
 		return GotifyClient{}, fmt.Errorf("failed to parse gotify url: %v", err)
 	}
 
-	return GotifyClient{
+	priority := gotifyDefaultPriority
+	if conf.Priority != nil {
+		priority = *conf.Priority
+	}
+
+	gotifyClient := GotifyClient{
 		url:   gotifyUrl,
-		token: conf.Token, // TODO validate this token?
+		token: conf.Token,
+
+		priority:      priority,
+		priorityRules: conf.PriorityRules,
 
 		client: gotify.NewClient(gotifyUrl, &http.Client{}),
-	}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gotifyValidateTokenTimeout)
+	defer cancel()
+	err = gotifyClient.validateToken(ctx)
+	if err != nil {
+		return GotifyClient{}, err
+	}
+
+	return gotifyClient, nil
+}
+
+// validateToken checks the application token is valid, so a bad token is found before a notification is sent.
+// Application tokens can only be used to create messages, so the token is checked by creating an empty message.
+// Gotify checks the token before the message, so a valid token gets a bad request error, and no message is created.
+//
+// If gotify cannot be reached, a warning is logged rather than returning an error,
+// so gotify being down for a moment does not stop twitchets from starting.
+func (g GotifyClient) validateToken(ctx context.Context) error {
+	params := message.NewCreateMessageParamsWithContext(ctx)
+	params.Body = &models.MessageExternal{}
+
+	_, err := g.client.Message.CreateMessage(params, auth.TokenAuth(g.token))
+
+	var badRequestErr *message.CreateMessageBadRequest
+	var unauthorizedErr *message.CreateMessageUnauthorized
+	var forbiddenErr *message.CreateMessageForbidden
+	switch {
+	case err == nil, errors.As(err, &badRequestErr):
+		return nil
+	case errors.As(err, &unauthorizedErr), errors.As(err, &forbiddenErr):
+		return errors.New("gotify token is not valid")
+	default:
+		slog.Warn(
+			"could not check gotify token",
+			"url", g.url.String(),
+			"error", err,
+		)
+		return nil
+	}
 }
@@ -1,6 +1,10 @@
 package notification_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -8,6 +12,7 @@ import (
 	"github.com/ahobsonsayers/twitchets/notification"
 	"github.com/ahobsonsayers/twitchets/test"
 	"github.com/joho/godotenv"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,6 +34,84 @@ func TestGotifySendTicketMessage(t *testing.T) {
 	require.NoError(t, err)
 
 	ticket := testNotificationTicket()
-	err = client.SendTicketNotification(ticket)
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
 	require.NoError(t, err)
 }
+
+const testGotifyToken = "test-token"
+
+// newFakeGotifyServer creates a fake gotify server, which checks tokens and messages like gotify does.
+// The priorities of created messages are sent to the returned channel.
+func newFakeGotifyServer(t *testing.T) (string, chan int) {
+	priorities := make(chan int, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost || r.URL.Path != "/message" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("X-Gotify-Key") != testGotifyToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Unauthorized","errorCode":401,"errorDescription":"you need to provide a valid access token"}`))
+			return
+		}
+
+		var message struct {
+			Message  string `json:"message"`
+			Priority int    `json:"priority"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&message)
+		require.NoError(t, err)
+
+		if message.Message == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"Bad Request","errorCode":400,"errorDescription":"Field 'message' is required"}`))
+			return
+		}
+
+		priorities <- message.Priority
+		_, _ = w.Write([]byte(`{"id":1,"appid":1,"message":"message","date":"2025-01-01T00:00:00Z"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, priorities
+}
+
+func TestGotifyValidateToken(t *testing.T) {
+	serverUrl, _ := newFakeGotifyServer(t)
+
+	_, err := notification.NewGotifyClient(config.GotifyConfig{Url: serverUrl, Token: testGotifyToken})
+	require.NoError(t, err)
+
+	_, err = notification.NewGotifyClient(config.GotifyConfig{Url: serverUrl, Token: "wrong"})
+	require.ErrorContains(t, err, "gotify token is not valid")
+}
+
+func TestGotifyPriority(t *testing.T) {
+	serverUrl, priorities := newFakeGotifyServer(t)
+	ticket := testNotificationTicket() // 25% discount
+
+	client, err := notification.NewGotifyClient(config.GotifyConfig{Url: serverUrl, Token: testGotifyToken})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+	require.Equal(t, 5, <-priorities)
+
+	client, err = notification.NewGotifyClient(config.GotifyConfig{
+		Url:      serverUrl,
+		Token:    testGotifyToken,
+		Priority: lo.ToPtr(0),
+		PriorityRules: []config.PriorityRule{
+			{Priority: 10, MinDiscount: 50},
+			{Priority: 8, MinDiscount: 20},
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+	require.Equal(t, 8, <-priorities)
+}
@@ -0,0 +1,350 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	mqttDefaultClientId        = "twitchets"
+	mqttDefaultDiscoveryPrefix = "homeassistant"
+
+	// Time to wait for in-flight messages to be sent when disconnecting, in milliseconds
+	mqttDisconnectQuiesce = 250
+)
+
+// mqttInvalidIdCharacters matches characters that cannot be used in home assistant ids
+var mqttInvalidIdCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// MqttPayload is the JSON document published for each match.
+// Values are flat, so they are easy to use in home automations.
+type MqttPayload struct {
+	Title           string    `json:"title"`
+	Message         string    `json:"message"`
+	Link            string    `json:"link"`
+	Event           string    `json:"event"` // Event of the ticket listing config the listing matched
+	Venue           string    `json:"venue"`
+	Location        string    `json:"location"`
+	Date            string    `json:"date"`
+	Time            string    `json:"time"`
+	TicketType      string    `json:"ticketType"`
+	NumTickets      int       `json:"numTickets"`
+	TicketPrice     string    `json:"ticketPrice"`
+	TotalPrice      string    `json:"totalPrice"`
+	Discount        string    `json:"discount"`
+	DiscountPercent float64   `json:"discountPercent"`
+	AcceptsOffers   bool      `json:"acceptsOffers"`
+	ListedAt        time.Time `json:"listedAt"`
+	MatchedAt       time.Time `json:"matchedAt"`
+}
+
+// mqttDiscoveryConfig is a home assistant MQTT discovery config for a sensor
+// See https://www.home-assistant.io/integrations/sensor.mqtt/
+type mqttDiscoveryConfig struct {
+	Name                string              `json:"name"`
+	UniqueId            string              `json:"unique_id"`
+	StateTopic          string              `json:"state_topic"`
+	ValueTemplate       string              `json:"value_template"`
+	JsonAttributesTopic string              `json:"json_attributes_topic"`
+	DeviceClass         string              `json:"device_class"`
+	Icon                string              `json:"icon"`
+	Device              mqttDiscoveryDevice `json:"device"`
+}
+
+type mqttDiscoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+type MqttClient struct {
+	options *mqtt.ClientOptions
+	topic   string
+	qos     byte
+	retain  bool
+
+	homeAssistantDiscovery bool
+	discoveryPrefix        string
+
+	templates Templates
+
+	conn *mqttConnection
+}
+
+var (
+	_ Client    = MqttClient{}
+	_ Announcer = MqttClient{}
+	_ io.Closer = MqttClient{}
+)
+
+// mqttConnection is the connection to the broker shared by all sends of a client.
+// Brokers only allow one connection per client id, so separate connections
+// for concurrent sends would take over each other's session.
+type mqttConnection struct {
+	mu     sync.Mutex
+	client mqtt.Client
+	closed bool
+}
+
+func (c MqttClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) error {
+	payload, err := c.renderPayload(ticket, listingConfig)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mqtt payload: %w", err)
+	}
+
+	messages := []mqttMessage{{topic: c.topic, payload: body, retain: c.retain}}
+
+	// Event topics are always retained, so sensors show the last match after home assistant restarts
+	if c.homeAssistantDiscovery {
+		messages = append(messages, mqttMessage{
+			topic:   c.eventTopic(listingConfig.Event),
+			payload: body,
+			retain:  true,
+		})
+	}
+
+	return c.publish(ctx, messages...)
+}
+
+// AnnounceTicketListingConfigs publishes home assistant discovery configs,
+// creating a last match sensor for the event of each ticket listing config.
+// Nothing is published if home assistant discovery is not enabled.
+func (c MqttClient) AnnounceTicketListingConfigs(
+	ctx context.Context,
+	listingConfigs []config.TicketListingConfig,
+) error {
+	if !c.homeAssistantDiscovery {
+		return nil
+	}
+
+	messages := make([]mqttMessage, 0, len(listingConfigs))
+	for _, listingConfig := range listingConfigs {
+		discoveryConfig, err := json.Marshal(c.discoveryConfig(listingConfig.Event))
+		if err != nil {
+			return fmt.Errorf("failed to marshal mqtt discovery config: %w", err)
+		}
+
+		// Discovery configs must be retained, so home assistant gets them when it restarts
+		messages = append(messages, mqttMessage{
+			topic:   c.discoveryTopic(listingConfig.Event),
+			payload: discoveryConfig,
+			retain:  true,
+		})
+	}
+
+	return c.publish(ctx, messages...)
+}
+
+func (c MqttClient) renderPayload(
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) (MqttPayload, error) {
+	title, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return MqttPayload{}, err
+	}
+
+	message, err := RenderMessage(ticket, WithHeader(), WithFooter(), WithTemplate(c.templates.Message))
+	if err != nil {
+		return MqttPayload{}, err
+	}
+
+	data := newMessageTemplateData(ticket)
+
+	return MqttPayload{
+		Title:           title,
+		Message:         message,
+		Link:            ticket.URL(),
+		Event:           listingConfig.Event,
+		Venue:           data.Venue,
+		Location:        data.Location,
+		Date:            data.Date,
+		Time:            data.Time,
+		TicketType:      data.TicketType,
+		NumTickets:      data.NumTickets,
+		TicketPrice:     data.TotalTicketPrice,
+		TotalPrice:      data.TotalPrice,
+		Discount:        data.Discount,
+		DiscountPercent: data.DiscountPercent,
+		AcceptsOffers:   data.AcceptsOffers,
+		ListedAt:        data.ListedAt,
+		MatchedAt:       time.Now(),
+	}, nil
+}
+
+func (c MqttClient) discoveryConfig(event string) mqttDiscoveryConfig {
+	nodeId := c.nodeId()
+	return mqttDiscoveryConfig{
+		Name:                event + " Last Match",
+		UniqueId:            nodeId + "_" + mqttId(event),
+		StateTopic:          c.eventTopic(event),
+		ValueTemplate:       "{{ value_json.matchedAt }}",
+		JsonAttributesTopic: c.eventTopic(event),
+		DeviceClass:         "timestamp",
+		Icon:                "mdi:ticket",
+		Device: mqttDiscoveryDevice{
+			Identifiers:  []string{nodeId},
+			Name:         "Twitchets",
+			Manufacturer: "Twitchets",
+		},
+	}
+}
+
+// eventTopic gets the topic matches for an event are published to, for use by home assistant
+func (c MqttClient) eventTopic(event string) string {
+	return c.topic + "/" + mqttId(event)
+}
+
+// discoveryTopic gets the topic to publish the home assistant discovery config for an event to.
+// See https://www.home-assistant.io/integrations/mqtt/#discovery-topic
+func (c MqttClient) discoveryTopic(event string) string {
+	return fmt.Sprintf("%s/sensor/%s/%s/config", c.discoveryPrefix, c.nodeId(), mqttId(event))
+}
+
+// nodeId gets the id used to group the sensors of this client in home assistant.
+// This is based on the topic, so clients publishing to different topics do not clash.
+func (c MqttClient) nodeId() string {
+	return "twitchets_" + mqttId(c.topic)
+}
+
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// publish messages to the broker, connecting to it if not already connected
+func (c MqttClient) publish(ctx context.Context, messages ...mqttMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		err := waitForMqttToken(ctx, client.Publish(message.topic, c.qos, message.retain, message.payload))
+		if err != nil {
+			return fmt.Errorf("failed to publish to mqtt topic '%s': %w", message.topic, err)
+		}
+	}
+
+	return nil
+}
+
+// connect gets the connection to the broker.
+// A new connection is made if there is no connection yet, or the previous one was lost.
+func (c MqttClient) connect(ctx context.Context) (mqtt.Client, error) {
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	if c.conn.closed {
+		return nil, errors.New("mqtt client is closed")
+	}
+
+	if c.conn.client != nil {
+		if c.conn.client.IsConnectionOpen() {
+			return c.conn.client, nil
+		}
+		c.conn.client.Disconnect(0)
+	}
+
+	client := mqtt.NewClient(c.options)
+	err := waitForMqttToken(ctx, client.Connect())
+	if err != nil {
+		client.Disconnect(0)
+		return nil, fmt.Errorf("failed to connect to mqtt broker: %w", err)
+	}
+
+	c.conn.client = client
+	return client, nil
+}
+
+// Clients are closed once the config has changed and they are no longer used. No messages can be published once closed.
+// Clients are closed when the config changes, as new clients are created. No messages can be published once closed.
+func (c MqttClient) Close() error {
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	c.conn.closed = true
+	if c.conn.client != nil {
+		c.conn.client.Disconnect(mqttDisconnectQuiesce)
+		c.conn.client = nil
+	}
+
+	return nil
+}
+
+// waitForMqttToken waits for an mqtt operation to complete, or the context to be done
+func waitForMqttToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// mqttId converts text to an id that can be used in topics and by home assistant
+func mqttId(text string) string {
+	id := mqttInvalidIdCharacters.ReplaceAllString(strings.ToLower(text), "_")
+	return strings.Trim(id, "_")
+}
+
+func NewMqttClient(conf config.MqttConfig) (MqttClient, error) {
+	if conf.Qos < 0 || conf.Qos > 2 {
+		return MqttClient{}, errors.New("qos must be 0, 1 or 2")
+	}
+
+	clientId := conf.ClientId
+	if clientId == "" {
+		clientId = mqttDefaultClientId
+	}
+
+	discoveryPrefix := conf.DiscoveryPrefix
+	if discoveryPrefix == "" {
+		discoveryPrefix = mqttDefaultDiscoveryPrefix
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(conf.Broker).
+		SetClientID(clientId).
+		SetUsername(conf.Username).
+		SetPassword(conf.Password).
+		SetAutoReconnect(false).
+		SetConnectRetry(false)
+
+	return MqttClient{
+		options: options,
+		topic:   conf.Topic,
+		qos:     byte(conf.Qos),
+		retain:  conf.Retain,
+
+		homeAssistantDiscovery: conf.HomeAssistantDiscovery,
+		discoveryPrefix:        discoveryPrefix,
+
+		conn: &mqttConnection{},
+	}, nil
+}
@@ -0,0 +1,183 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/require"
+)
+
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// newMqttBroker starts a broker, returning its url and the messages published to it
+func newMqttBroker(t *testing.T) (string, <-chan mqttMessage) {
+	broker := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, broker.AddHook(new(auth.AllowHook), nil))
+
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	require.NoError(t, broker.AddListener(listener))
+	require.NoError(t, broker.Serve())
+	t.Cleanup(func() { _ = broker.Close() })
+
+	messages := make(chan mqttMessage, 10)
+	err := broker.Subscribe("#", 1, func(_ *mqttserver.Client, _ packets.Subscription, packet packets.Packet) {
+		messages <- mqttMessage{
+			topic:   packet.TopicName,
+			payload: packet.Payload,
+			retain:  packet.FixedHeader.Retain,
+		}
+	})
+	require.NoError(t, err)
+
+	return "tcp://" + listener.Address(), messages
+}
+
+func receiveMqttMessage(t *testing.T, messages <-chan mqttMessage) mqttMessage {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for mqtt message")
+		return mqttMessage{}
+	}
+}
+
+func TestMqttSendTicketMessage(t *testing.T) {
+	brokerUrl, messages := newMqttBroker(t)
+
+	client, err := notification.NewMqttClient(config.MqttConfig{
+		Broker:                 brokerUrl,
+		Topic:                  "twitchets/matches",
+		Qos:                    1,
+		Retain:                 true,
+		HomeAssistantDiscovery: true,
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	listingConfig := config.TicketListingConfig{Event: "Test Event!"}
+	err = client.SendTicketNotification(context.Background(), ticket, listingConfig)
+	require.NoError(t, err)
+
+	message := receiveMqttMessage(t, messages)
+	require.Equal(t, "twitchets/matches", message.topic)
+	require.True(t, message.retain)
+
+	var payload notification.MqttPayload
+	err = json.Unmarshal(message.payload, &payload)
+	require.NoError(t, err)
+	require.Equal(t, "Test Event", payload.Title)
+	require.Equal(t, "Test Event!", payload.Event)
+	require.Equal(t, ticket.URL(), payload.Link)
+	require.Equal(t, 2, payload.NumTickets)
+	require.InDelta(t, 25.0, payload.DiscountPercent, 0.01)
+
+	// Matches are also published to the event topic, for home assistant sensors
+	message = receiveMqttMessage(t, messages)
+	require.Equal(t, "twitchets/matches/test_event", message.topic)
+	require.True(t, message.retain)
+}
+
+func TestMqttAnnounceTicketListingConfigs(t *testing.T) {
+	brokerUrl, messages := newMqttBroker(t)
+
+	client, err := notification.NewMqttClient(config.MqttConfig{
+		Broker:                 brokerUrl,
+		Topic:                  "twitchets",
+		HomeAssistantDiscovery: true,
+	})
+	require.NoError(t, err)
+
+	err = client.AnnounceTicketListingConfigs(
+		context.Background(),
+		[]config.TicketListingConfig{{Event: "Taylor Swift"}},
+	)
+	require.NoError(t, err)
+
+	message := receiveMqttMessage(t, messages)
+	require.Equal(t, "homeassistant/sensor/twitchets_twitchets/taylor_swift/config", message.topic)
+	require.True(t, message.retain)
+
+	var discoveryConfig map[string]any
+	err = json.Unmarshal(message.payload, &discoveryConfig)
+	require.NoError(t, err)
+	require.Equal(t, "Taylor Swift Last Match", discoveryConfig["name"])
+	require.Equal(t, "twitchets_twitchets_taylor_swift", discoveryConfig["unique_id"])
+	require.Equal(t, "twitchets/taylor_swift", discoveryConfig["state_topic"])
+	require.Equal(t, "timestamp", discoveryConfig["device_class"])
+}
+
+func TestMqttAnnounceTicketListingConfigsWithoutDiscovery(t *testing.T) {
+	brokerUrl, messages := newMqttBroker(t)
+
+	client, err := notification.NewMqttClient(config.MqttConfig{Broker: brokerUrl, Topic: "twitchets"})
+	require.NoError(t, err)
+
+	err = client.AnnounceTicketListingConfigs(
+		context.Background(),
+		[]config.TicketListingConfig{{Event: "Taylor Swift"}},
+	)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+}
+
+func TestMqttSendTicketMessageConcurrently(t *testing.T) {
+	brokerUrl, messages := newMqttBroker(t)
+
+	client, err := notification.NewMqttClient(config.MqttConfig{Broker: brokerUrl, Topic: "twitchets", Qos: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	// Concurrent sends should share a connection, rather than taking over each other's session
+	const numSends = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numSends)
+	for range numSends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- client.SendTicketNotification(
+				context.Background(),
+				testNotificationTicket(),
+				config.TicketListingConfig{Event: "Test Event"},
+			)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	for range numSends {
+		message := receiveMqttMessage(t, messages)
+		require.Equal(t, "twitchets", message.topic)
+	}
+
+	// No messages can be sent once closed
+	require.NoError(t, client.Close())
+	err = client.SendTicketNotification(
+		context.Background(),
+		testNotificationTicket(),
+		config.TicketListingConfig{Event: "Test Event"},
+	)
+	require.ErrorContains(t, err, "mqtt client is closed")
+}
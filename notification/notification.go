@@ -1,15 +1,19 @@
 package notification
 
 import (
-	"bytes"
+	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/samber/lo"
 )
 
 var (
@@ -19,15 +23,28 @@ var (
 )
 
 func init() {
-	var err error
-	messageTemplate, err = template.ParseFS(messageTemplateFS, "templates/message.tmpl.md")
+	messageTemplateText, err := messageTemplateFS.ReadFile("templates/message.tmpl.md")
 	if err != nil {
 		log.Fatalf("failed to read notification message template: %v", err)
 	}
+
+	// The default template only uses functions for markup, so its text can be escaped
+	messageTemplate, err = parseFormattedTemplate("message.tmpl.md", string(messageTemplateText), true)
+	if err != nil {
+		log.Fatalf("failed to parse notification message template: %v", err)
+	}
 }
 
+// Client sends ticket notifications for ticket listings, and the ticket listing config they matched.
+// Sending should stop and return an error if the context is cancelled or its deadline is exceeded.
 type Client interface {
-	SendTicketNotification(twigots.TicketListing) error
+	SendTicketNotification(context.Context, twigots.TicketListing, config.TicketListingConfig) error
+}
+
+// Announcer is a client that announces the ticket listing configs it sends notifications for,
+// so they can be discovered in advance e.g. by home automation software.
+type Announcer interface {
+	AnnounceTicketListingConfigs(context.Context, []config.TicketListingConfig) error
 }
 
 type MessageTemplateData struct {
@@ -50,11 +67,22 @@ type MessageTemplateData struct {
 
 	// Footer
 	Link string
+
+	// Raw values, for use in custom templates
+	DiscountPercent          float64 // e.g. 25.5 for 25.5% off. Negative if there is no discount.
+	TicketPricePence         int     // Including fee
+	TotalPricePence          int     // Including fee
+	OriginalTicketPricePence int
+	OriginalTotalPricePence  int
+	ListedAt                 time.Time
+	ListingAge               time.Duration
 }
 
 type renderMessageConfig struct {
 	includeHeader bool
 	includeFooter bool
+	template      *template.Template
+	format        Format
 }
 
 func newRenderMessageConfig(options ...RenderMessageOption) renderMessageConfig {
@@ -83,10 +111,56 @@ func WithFooter() RenderMessageOption {
 	}
 }
 
+// Template to render the message with, instead of the default.
+// Custom templates always include the event name header and buy link footer.
+// If the template is nil, the default is used.
+func WithTemplate(tmpl *template.Template) RenderMessageOption {
+	return func(o *renderMessageConfig) {
+		o.template = tmpl
+	}
+}
+
+// Format to render the message in, so values are escaped and markup is added for how the message is displayed.
+// If not set, the message is rendered as markdown.
+func WithFormat(format Format) RenderMessageOption {
+	return func(o *renderMessageConfig) {
+		o.format = format
+	}
+}
+
 func RenderMessage(ticket twigots.TicketListing, options ...RenderMessageOption) (string, error) {
 	conf := newRenderMessageConfig(options...)
 
-	templateData := MessageTemplateData{
+	tmpl := messageTemplate
+	if conf.template != nil {
+		tmpl = conf.template
+		conf.includeHeader = true
+		conf.includeFooter = true
+	}
+
+	templateData := newMessageTemplateData(ticket)
+
+	// Add optional header and footers
+	if conf.includeHeader {
+		templateData.Event = ticket.Event.Name
+	}
+
+	if conf.includeFooter {
+		templateData.Link = ticket.URL()
+	}
+
+	message, err := executeFormattedTemplate(tmpl, conf.format, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to render notification message template:, %w", err)
+	}
+
+	message = strings.TrimSpace(message)
+
+	return message, nil
+}
+
+func newMessageTemplateData(ticket twigots.TicketListing) MessageTemplateData {
+	return MessageTemplateData{
 		Date:                ticket.Event.Date.Format("Monday 2 January 2006"),
 		Time:                ticket.Event.Time.Format("3:04pm"),
 		Venue:               ticket.Event.Venue.Name,
@@ -99,58 +173,212 @@ func RenderMessage(ticket twigots.TicketListing, options ...RenderMessageOption)
 		OriginalTotalPrice:  ticket.OriginalTotalPrice.String(),
 		Discount:            ticket.DiscountString(),
 		AcceptsOffers:       ticket.SellerWillConsiderOffers,
-	}
 
-	// Add optional header and footers
-	if conf.includeHeader {
-		templateData.Event = ticket.Event.Name
+		DiscountPercent:          ticket.Discount() * 100,
+		TicketPricePence:         ticket.TicketPriceInclFee().Amount,
+		TotalPricePence:          ticket.TotalPriceInclFee().Amount,
+		OriginalTicketPricePence: ticket.OriginalTicketPrice().Amount,
+		OriginalTotalPricePence:  ticket.OriginalTotalPrice.Amount,
+		ListedAt:                 ticket.CreatedAt.Time,
+		ListingAge:               time.Since(ticket.CreatedAt.Time),
 	}
+}
 
-	if conf.includeFooter {
-		templateData.Link = ticket.URL()
+// GetNotificationClients creates a client for each notifier, keyed by notifier name
+func GetNotificationClients(notifiers []config.NotifierConfig) (map[string]Client, error) {
+	clients := make(map[string]Client, len(notifiers))
+	for _, notifier := range notifiers {
+		client, err := NewNotificationClient(notifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup notifier '%s': %w", notifier.Name, err)
+		}
+
+		clients[notifier.Name] = client
 	}
 
-	var buffer bytes.Buffer
-	err := messageTemplate.Execute(&buffer, templateData)
-	if err != nil {
-		return "", fmt.Errorf("failed to render notification message template:, %w", err)
+	return clients, nil
+}
+
+// AnnounceTicketListingConfigs announces ticket listing configs to the clients that are announcers.
+// Each announcer is only told about the ticket listing configs that notify it.
+func AnnounceTicketListingConfigs(
+	ctx context.Context,
+	clients map[string]Client,
+	listingConfigs []config.TicketListingConfig,
+) error {
+	var errs []error
+	for name, client := range clients {
+		announcer, ok := client.(Announcer)
+		if !ok {
+			continue
+		}
+
+		announcedConfigs := lo.Filter(listingConfigs, func(listingConfig config.TicketListingConfig, _ int) bool {
+			return listingConfig.UsesNotifier(name)
+		})
+
+		err := announcer.AnnounceTicketListingConfigs(ctx, announcedConfigs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to announce to notifier '%s': %w", name, err))
+		}
 	}
 
-	message := buffer.String()
-	message = strings.TrimSpace(message)
+	return errors.Join(errs...)
+}
 
-	return message, nil
+// CloseNotificationClients closes the clients that hold resources such as connections.
+// Clients cannot be used once closed.
+func CloseNotificationClients(clients map[string]Client) error {
+	var errs []error
+	for name, client := range clients {
+		closer, ok := client.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		err := closer.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close notifier '%s': %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
-func GetNotificationClients(conf config.NotificationConfig) (map[config.NotificationType]Client, error) {
-	clients := map[config.NotificationType]Client{}
+// NewNotificationClient creates a client for a notifier, using the settings of its type
+func NewNotificationClient(notifier config.NotifierConfig) (Client, error) {
+	notifier, err := notifier.ExpandUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := NewTemplates(notifier)
+	if err != nil {
+		return nil, err
+	}
+
+	switch notifier.Type {
+	case config.NotificationTypeNtfy:
+		if notifier.Ntfy == nil {
+			return nil, errors.New("ntfy settings are not set")
+		}
 
-	if conf.Ntfy != nil {
-		ntfyClient, err := NewNtfyClient(*conf.Ntfy)
+		ntfyClient, err := NewNtfyClient(*notifier.Ntfy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to setup ntfy client: %w", err)
 		}
+		ntfyClient.templates = templates
+		return ntfyClient, nil
 
-		clients[config.NotificationTypeNtfy] = ntfyClient
-	}
+	case config.NotificationTypeGotify:
+		if notifier.Gotify == nil {
+			return nil, errors.New("gotify settings are not set")
+		}
 
-	if conf.Gotify != nil {
-		gotifyClient, err := NewGotifyClient(*conf.Gotify)
+		gotifyClient, err := NewGotifyClient(*notifier.Gotify)
 		if err != nil {
 			return nil, fmt.Errorf("failed to setup gotify client: %w", err)
 		}
+		gotifyClient.templates = templates
+		return gotifyClient, nil
 
-		clients[config.NotificationTypeGotify] = gotifyClient
-	}
+	case config.NotificationTypeTelegram:
+		if notifier.Telegram == nil {
+			return nil, errors.New("telegram settings are not set")
+		}
 
-	if conf.Telegram != nil {
-		telegramClient, err := NewTelegramClient(*conf.Telegram)
+		telegramClient, err := NewTelegramClient(*notifier.Telegram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to setup telegram client: %w", err)
 		}
+		telegramClient.templates = templates
+		return telegramClient, nil
 
-		clients[config.NotificationTypeTelegram] = telegramClient
-	}
+	case config.NotificationTypeWebhook:
+		if notifier.Webhook == nil {
+			return nil, errors.New("webhook settings are not set")
+		}
 
-	return clients, nil
+		webhookClient, err := NewWebhookClient(*notifier.Webhook)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup webhook client: %w", err)
+		}
+		webhookClient.templates = templates
+		return webhookClient, nil
+
+	case config.NotificationTypeDiscord:
+		if notifier.Discord == nil {
+			return nil, errors.New("discord settings are not set")
+		}
+
+		discordClient, err := NewDiscordClient(*notifier.Discord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup discord client: %w", err)
+		}
+		discordClient.templates = templates
+		return discordClient, nil
+
+	case config.NotificationTypeSlack:
+		if notifier.Slack == nil {
+			return nil, errors.New("slack settings are not set")
+		}
+
+		slackClient, err := NewSlackClient(*notifier.Slack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup slack client: %w", err)
+		}
+		slackClient.templates = templates
+		return slackClient, nil
+
+	case config.NotificationTypeEmail:
+		if notifier.Email == nil {
+			return nil, errors.New("email settings are not set")
+		}
+
+		emailClient, err := NewEmailClient(*notifier.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup email client: %w", err)
+		}
+		emailClient.templates = templates
+		return emailClient, nil
+
+	case config.NotificationTypeMqtt:
+		if notifier.Mqtt == nil {
+			return nil, errors.New("mqtt settings are not set")
+		}
+
+		mqttClient, err := NewMqttClient(*notifier.Mqtt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup mqtt client: %w", err)
+		}
+		mqttClient.templates = templates
+		return mqttClient, nil
+
+	case config.NotificationTypeExec:
+		if notifier.Exec == nil {
+			return nil, errors.New("exec settings are not set")
+		}
+
+		execClient, err := NewExecClient(*notifier.Exec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup exec client: %w", err)
+		}
+		execClient.templates = templates
+		return execClient, nil
+
+	case config.NotificationTypePushover:
+		if notifier.Pushover == nil {
+			return nil, errors.New("pushover settings are not set")
+		}
+
+		pushoverClient, err := NewPushoverClient(*notifier.Pushover)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup pushover client: %w", err)
+		}
+		pushoverClient.templates = templates
+		return pushoverClient, nil
+
+	default:
+		return nil, fmt.Errorf("notification type '%s' is not supported", notifier.Type.Value)
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
 	"github.com/ahobsonsayers/twitchets/notification"
 	"github.com/ahobsonsayers/twitchets/test"
 	"github.com/stretchr/testify/require"
@@ -73,3 +74,76 @@ func TestRenderMessageWithHeaderAndFooter(t *testing.T) {
 
 	require.Equal(t, expectedMessage, actualMessage)
 }
+
+func TestRenderMessageWithTemplate(t *testing.T) {
+	templates, err := notification.NewTemplates(config.NotifierConfig{
+		Template:      "{{ .Event }}: {{ .NumTickets }} for {{ .TotalPricePence }}p ({{ printf \"%.0f\" .DiscountPercent }}% off)",
+		TitleTemplate: "Tickets for {{ .Event }}",
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	actualMessage, err := notification.RenderMessage(ticket, notification.WithTemplate(templates.Message))
+	require.NoError(t, err)
+	require.Equal(t, "Test Event: 2 for 300p (25% off)", actualMessage)
+
+	actualTitle, err := notification.RenderTitle(ticket, templates.Title)
+	require.NoError(t, err)
+	require.Equal(t, "Tickets for Test Event", actualTitle)
+}
+
+func TestNewTemplatesWithUnknownField(t *testing.T) {
+	_, err := notification.NewTemplates(config.NotifierConfig{
+		Template: "{{ .Unknown }}",
+	})
+	require.ErrorContains(t, err, "template is not valid")
+}
+
+func TestNewNotificationClientFromUrl(t *testing.T) {
+	client, err := notification.NewNotificationClient(config.NotifierConfig{
+		Name: "pushover",
+		Url:  "pushover://user@token",
+	})
+	require.NoError(t, err)
+	require.IsType(t, notification.PushoverClient{}, client)
+}
+
+func TestRenderDigest(t *testing.T) {
+	expectedDigestPath := test.ProjectDirectoryJoin(t, "test", "data", "message", "digest.md")
+	expectedDigestBytes, err := os.ReadFile(expectedDigestPath)
+	require.NoError(t, err)
+	expectedDigest := string(expectedDigestBytes)
+
+	actualDigest, err := notification.RenderDigest(testDigestTickets(), nil, notification.FormatMarkdown)
+	require.NoError(t, err)
+
+	require.Equal(t, expectedDigest, actualDigest)
+}
+
+func TestRenderDigestWithTemplate(t *testing.T) {
+	templates, err := notification.NewTemplates(config.NotifierConfig{
+		DigestTemplate: "{{ range .Events }}{{ .Event }}: {{ range .Listings }}{{ .TotalTicketPrice }} {{ end }}\n{{ end }}",
+	})
+	require.NoError(t, err)
+
+	actualDigest, err := notification.RenderDigest(testDigestTickets(), templates.Digest, notification.FormatPlain)
+	require.NoError(t, err)
+	require.Equal(t, "Test Event: £1.00 £1.50 \nOther Event: £3.00", actualDigest)
+
+	require.Equal(t, "3 new ticket listing(s)", notification.RenderDigestTitle(testDigestTickets()))
+	require.Equal(t, "Test Event: 1 new ticket listing(s)", notification.RenderDigestTitle(testDigestTickets()[:1]))
+}
+
+// testDigestTickets gets ticket listings for two events, which are not listed in order of price
+func testDigestTickets() []twigots.TicketListing {
+	otherEventTicket := testNotificationTicket()
+	otherEventTicket.Id = "other"
+	otherEventTicket.Event.Name = "Other Event"
+	otherEventTicket.NumTickets = 1
+
+	cheaperTicket := testNotificationTicket()
+	cheaperTicket.Id = "cheaper"
+	cheaperTicket.TwicketsFee.Amount = 0
+
+	return []twigots.TicketListing{testNotificationTicket(), otherEventTicket, cheaperTicket}
+}
@@ -1,9 +1,14 @@
 package notification
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
@@ -15,32 +20,101 @@ type NtfyClient struct {
 	url      *url.URL
 	user     string
 	password string
+	token    string
 
-	client *client.Client
+	priority      int
+	priorityRules []config.PriorityRule
+	tags          []string
+	icon          string
+	delay         time.Duration
+
+	templates Templates
+	client    *client.Client
 }
 
-var _ Client = NtfyClient{}
+var _ DigestClient = NtfyClient{}
 
-func (c NtfyClient) SendTicketNotification(ticket twigots.TicketListing) error {
-	notificationMessage, err := RenderMessage(ticket)
+func (c NtfyClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderMessage(ticket, WithTemplate(c.templates.Message), WithFormat(FormatMarkdown))
 	if err != nil {
 		return err
 	}
 
-	opts := []client.PublishOption{
-		client.WithTitle(ticket.Event.Name),
+	notificationTitle, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return err
+	}
+
+	return c.publish(
+		ctx,
+		notificationTitle,
+		notificationMessage,
+		config.ListingPriority(ticket, c.priorityRules, c.priority),
 		client.WithActions(NtfyViewAction("Open Link", lo.ToPtr(ticket.URL()))),
+	)
+}
+
+func (c NtfyClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderDigest(tickets, c.templates.Digest, FormatMarkdown)
+	if err != nil {
+		return err
+	}
+
+	// Use the highest priority of the listings
+	priority := lo.Max(lo.Map(tickets, func(ticket twigots.TicketListing, _ int) int {
+		return config.ListingPriority(ticket, c.priorityRules, c.priority)
+	}))
+
+	return c.publish(ctx, RenderDigestTitle(tickets), notificationMessage, priority)
+}
+
+// publish a markdown message to the topic
+func (c NtfyClient) publish(
+	ctx context.Context,
+	title, message string,
+	priority int,
+	extraOpts ...client.PublishOption,
+) error {
+	opts := []client.PublishOption{
+		ntfyWithContext(ctx),
+		client.WithTitle(title),
 		client.WithHeader("Content-Type", "text/markdown"),
 	}
+	opts = append(opts, extraOpts...)
+
 	// If you try to set any auth on an unprotected topic, it will fail
-	//  so only set it if the user and password are set
+	//  so only set it if the user and password, or token are set
 	if c.user != "" && c.password != "" {
 		opts = append(opts, client.WithBasicAuth(c.user, c.password))
 	}
+	if c.token != "" {
+		opts = append(opts, client.WithHeader("Authorization", "Bearer "+c.token))
+	}
 
-	_, err = c.client.Publish(
+	if priority != 0 {
+		opts = append(opts, client.WithHeader("X-Priority", strconv.Itoa(priority)))
+	}
+	if len(c.tags) != 0 {
+		opts = append(opts, client.WithHeader("X-Tags", strings.Join(c.tags, ",")))
+	}
+	if c.icon != "" {
+		opts = append(opts, client.WithHeader("X-Icon", c.icon))
+	}
+	if c.delay != 0 {
+		opts = append(opts, client.WithHeader("X-Delay", fmt.Sprintf("%ds", int(c.delay.Seconds()))))
+	}
+
+	_, err := c.client.Publish(
 		c.url.String(),
-		notificationMessage,
+		message,
 		opts...,
 	)
 	if err != nil {
@@ -62,11 +136,27 @@ func NewNtfyClient(conf config.NtfyConfig) (NtfyClient, error) {
 		url:      ntfyUrl,
 		user:     conf.Username,
 		password: conf.Password,
+		token:    conf.Token,
+
+		priority:      conf.Priority,
+		priorityRules: conf.PriorityRules,
+		tags:          conf.Tags,
+		icon:          conf.Icon,
+		delay:         time.Duration(conf.Delay),
 
 		client: client.New(nil),
 	}, nil
 }
 
+// ntfyWithContext sets the context of a ntfy publish request.
+// The ntfy client does not accept a context, so it is set using a publish option.
+func ntfyWithContext(ctx context.Context) client.PublishOption {
+	return func(r *http.Request) error {
+		*r = *r.WithContext(ctx)
+		return nil
+	}
+}
+
 // NtfyViewAction creates a ntfy actions string for a single view actions
 // See https://docs.ntfy.sh/publish/#using-a-json-array
 func NtfyViewAction(label string, link *string, params ...map[string]string) string {
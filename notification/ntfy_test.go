@@ -1,8 +1,13 @@
 package notification_test
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/ahobsonsayers/twitchets/config"
 	"github.com/ahobsonsayers/twitchets/notification"
@@ -37,7 +42,7 @@ func TestNtfySendTicketMessage(t *testing.T) {
 	require.NoError(t, err)
 
 	ticket := testNotificationTicket()
-	err = client.SendTicketNotification(ticket)
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
 	require.NoError(t, err)
 }
 
@@ -61,6 +66,49 @@ func TestNtfySendTicketMessageWithoutAuth(t *testing.T) {
 	require.NoError(t, err)
 
 	ticket := testNotificationTicket()
-	err = client.SendTicketNotification(ticket)
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
 	require.NoError(t, err)
 }
+
+func TestNtfyHeaders(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NotEmpty(t, body)
+
+		requests <- r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","time":1735689600,"event":"message","topic":"tickets","message":"message"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := notification.NewNtfyClient(config.NtfyConfig{
+		Url:      server.URL,
+		Topic:    "tickets",
+		Token:    "test-token",
+		Priority: 3,
+		PriorityRules: []config.PriorityRule{
+			{Priority: 5, MinDiscount: 50},
+			{Priority: 4, MinDiscount: 20},
+		},
+		Tags:  []string{"ticket", "tada"},
+		Icon:  "https://example.com/icon.png",
+		Delay: config.Duration(10 * time.Minute),
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket() // 25% discount
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	request := <-requests
+	require.Equal(t, http.MethodPost, request.Method)
+	require.Equal(t, "/tickets", request.URL.Path)
+	require.Equal(t, "Bearer test-token", request.Header.Get("Authorization"))
+	require.Equal(t, "4", request.Header.Get("X-Priority"))
+	require.Equal(t, "ticket,tada", request.Header.Get("X-Tags"))
+	require.Equal(t, "https://example.com/icon.png", request.Header.Get("X-Icon"))
+	require.Equal(t, "600s", request.Header.Get("X-Delay"))
+	require.Equal(t, "text/markdown", request.Header.Get("Content-Type"))
+}
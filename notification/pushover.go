@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+const pushoverApiUrl = "https://api.pushover.net/1/messages.json"
+
+// Maximum lengths of pushover message fields
+// See https://pushover.net/api#limits
+const (
+	pushoverMaxTitleLength   = 250
+	pushoverMaxMessageLength = 1024
+)
+
+type PushoverClient struct {
+	user   string
+	token  string
+	device string
+
+	templates Templates
+	client    *http.Client
+}
+
+var _ DigestClient = PushoverClient{}
+
+// pushoverResponse is the body of a response from the pushover api
+// See https://pushover.net/api#response
+type pushoverResponse struct {
+	Status int      `json:"status"`
+	Errors []string `json:"errors"`
+}
+
+func (c PushoverClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderMessage(ticket, WithTemplate(c.templates.Message), WithFormat(FormatPlain))
+	if err != nil {
+		return err
+	}
+
+	notificationTitle, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, notificationTitle, notificationMessage, ticket.URL())
+}
+
+func (c PushoverClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	notificationMessage, err := RenderDigest(tickets, c.templates.Digest, FormatPlain)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, RenderDigestTitle(tickets), notificationMessage, "")
+}
+
+// send a message, with a link to a ticket listing if it is set
+func (c PushoverClient) send(ctx context.Context, title, message, link string) error {
+	form := url.Values{}
+	form.Set("token", c.token)
+	form.Set("user", c.user)
+	form.Set("title", truncate(title, pushoverMaxTitleLength))
+	form.Set("message", truncate(message, pushoverMaxMessageLength))
+	if link != "" {
+		form.Set("url", link)
+		form.Set("url_title", "Buy on Twickets")
+	}
+	if c.device != "" {
+		form.Set("device", c.device)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, pushoverApiUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create pushover request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read pushover response: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		var pushoverResponse pushoverResponse
+		err := json.Unmarshal(responseBody, &pushoverResponse)
+		if err != nil || len(pushoverResponse.Errors) == 0 {
+			return fmt.Errorf("pushover returned status %s: %s", response.Status, string(responseBody))
+		}
+		return fmt.Errorf("pushover returned status %s: %s", response.Status, strings.Join(pushoverResponse.Errors, ", "))
+	}
+
+	return nil
+}
+
+func NewPushoverClient(conf config.PushoverConfig) (PushoverClient, error) {
+	return PushoverClient{
+		user:   conf.User,
+		token:  conf.Token,
+		device: conf.Device,
+
+		client: http.DefaultClient,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package notification_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/test"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushoverSendTicketMessage(t *testing.T) {
+	t.Skip("Can only be run manually locally with environment variables set. Comment to run.")
+
+	_ = godotenv.Load(test.ProjectDirectoryJoin(t, ".env"))
+
+	pushoverUser := os.Getenv("PUSHOVER_USER")
+	require.NotEmpty(t, pushoverUser, "PUSHOVER_USER is not set")
+
+	pushoverToken := os.Getenv("PUSHOVER_TOKEN")
+	require.NotEmpty(t, pushoverToken, "PUSHOVER_TOKEN is not set")
+
+	client, err := notification.NewPushoverClient(config.PushoverConfig{
+		User:  pushoverUser,
+		Token: pushoverToken,
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+}
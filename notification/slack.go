@@ -0,0 +1,215 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+// Maximum length of the text of slack header and section blocks
+const (
+	slackMaxHeaderLength  = 150
+	slackMaxSectionLength = 3000
+)
+
+type SlackClient struct {
+	webhookUrl string
+
+	templates Templates
+	client    *http.Client
+}
+
+var _ DigestClient = SlackClient{}
+
+// slackMessage is a Block Kit message sent to a slack incoming webhook.
+// Text is shown in notifications, and by clients that cannot show blocks.
+// See https://api.slack.com/reference/block-kit/blocks
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Fields   []slackText    `json:"fields,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"` // plain_text or mrkdwn
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	Url  string    `json:"url"`
+}
+
+func (c SlackClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	message, err := c.renderMessage(ticket)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, message)
+}
+
+func (c SlackClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	digest, err := RenderDigest(tickets, c.templates.Digest, FormatSlackMrkdwn)
+	if err != nil {
+		return err
+	}
+
+	title := RenderDigestTitle(tickets)
+	return c.send(ctx, slackMessage{
+		Text: FormatSlackMrkdwn.Escape(title),
+		Blocks: []slackBlock{
+			{
+				Type: "header",
+				Text: &slackText{Type: "plain_text", Text: truncate(title, slackMaxHeaderLength)},
+			},
+			{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: truncate(digest, slackMaxSectionLength)},
+			},
+		},
+	})
+}
+
+// send a message to the webhook
+func (c SlackClient) send(ctx context.Context, message slackMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read slack response: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("slack returned status %s: %s", response.Status, string(responseBody))
+	}
+
+	return nil
+}
+
+func (c SlackClient) renderMessage(ticket twigots.TicketListing) (slackMessage, error) {
+	title, err := RenderTitle(ticket, c.templates.Title)
+	if err != nil {
+		return slackMessage{}, err
+	}
+
+	data := newMessageTemplateData(ticket)
+
+	price := data.TotalTicketPrice + " per ticket"
+	if data.NumTickets > 1 {
+		price += fmt.Sprintf(" (%s total)", data.TotalPrice)
+	}
+	if data.AcceptsOffers {
+		price += "\nOffers Accepted"
+	}
+
+	blocks := []slackBlock{
+		{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncate(title, slackMaxHeaderLength)},
+		},
+		{
+			Type: "section",
+			Fields: []slackText{
+				slackField("Date", fmt.Sprintf("%s %s", data.Date, data.Time)),
+				slackField("Venue", fmt.Sprintf("%s, %s", data.Venue, data.Location)),
+				slackField("Tickets", fmt.Sprintf("%d - %s", data.NumTickets, data.TicketType)),
+				slackField("Price", price),
+				slackField("Discount", data.Discount),
+			},
+		},
+	}
+
+	// Only add the message if there is a custom message template,
+	// as the default message has the same details as the fields.
+	// Custom templates are written by the user, so should already use slack formatting,
+	// but the values they output are escaped.
+	if c.templates.Message != nil {
+		messageText, err := RenderMessage(ticket, WithTemplate(c.templates.Message), WithFormat(FormatSlackMrkdwn))
+		if err != nil {
+			return slackMessage{}, err
+		}
+
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: messageText},
+		})
+	}
+
+	blocks = append(blocks, slackBlock{
+		Type: "actions",
+		Elements: []slackElement{
+			{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: "View on Twickets"},
+				Url:  ticket.URL(),
+			},
+		},
+	})
+
+	return slackMessage{
+		Text:   FormatSlackMrkdwn.Escape(title),
+		Blocks: blocks,
+	}, nil
+}
+
+// slackField creates a mrkdwn field with a bold name, and an escaped value below it
+func slackField(name, value string) slackText {
+	return slackText{
+		Type: "mrkdwn",
+		Text: fmt.Sprintf("*%s*\n%s", name, FormatSlackMrkdwn.Escape(value)),
+	}
+}
+
+// truncate text to a max number of characters, adding an ellipsis if it was truncated
+func truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
+
+func NewSlackClient(conf config.SlackConfig) (SlackClient, error) {
+	return SlackClient{
+		webhookUrl: conf.WebhookUrl,
+
+		client: http.DefaultClient,
+	}, nil
+}
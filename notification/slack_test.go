@@ -0,0 +1,152 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackSendTicketMessage(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies <- body
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := notification.NewSlackClient(config.SlackConfig{WebhookUrl: server.URL})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	ticket.Event.Venue.Name = "Bar & Grill <Upstairs>"
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	var message struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+			Fields []struct {
+				Text string `json:"text"`
+			} `json:"fields"`
+			Elements []struct {
+				Url string `json:"url"`
+			} `json:"elements"`
+		} `json:"blocks"`
+	}
+	err = json.Unmarshal(<-bodies, &message)
+	require.NoError(t, err)
+
+	require.Equal(t, "Test Event", message.Text)
+	require.Len(t, message.Blocks, 3)
+
+	require.Equal(t, "header", message.Blocks[0].Type)
+	require.Equal(t, "Test Event", message.Blocks[0].Text.Text)
+
+	require.Equal(t, "section", message.Blocks[1].Type)
+	require.Equal(t, "*Venue*\nBar &amp; Grill &lt;Upstairs&gt;, Test Location", message.Blocks[1].Fields[1].Text)
+
+	require.Equal(t, "actions", message.Blocks[2].Type)
+	require.Equal(t, ticket.URL(), message.Blocks[2].Elements[0].Url)
+}
+
+func TestSlackSendTicketMessageWithTemplate(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies <- body
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := notification.NewNotificationClient(config.NotifierConfig{
+		Name:     "slack",
+		Type:     config.NotificationTypeSlack,
+		Template: "_{{ .Event }}_ at {{ .Venue }}. {{ link \"Buy\" .Link }}",
+		Slack:    &config.SlackConfig{WebhookUrl: server.URL},
+	})
+	require.NoError(t, err)
+
+	// Values should be escaped, but the text of the template should not
+	err = client.SendTicketNotification(context.Background(), testFormatTicket(), config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	var message struct {
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	err = json.Unmarshal(<-bodies, &message)
+	require.NoError(t, err)
+
+	require.Len(t, message.Blocks, 4)
+	require.Equal(t, "section", message.Blocks[2].Type)
+	require.Equal(
+		t,
+		"_The_Eras *Tour* [Live] (2025)_ at Bar &amp; Grill &lt;Upstairs&gt;. "+
+			"<https://www.twickets.live/app/block/test,2|Buy>",
+		message.Blocks[2].Text.Text,
+	)
+}
+
+func TestSlackSendDigest(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies <- body
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := notification.NewSlackClient(config.SlackConfig{WebhookUrl: server.URL})
+	require.NoError(t, err)
+
+	tickets := testDigestTickets()
+	tickets[0].Event.Name = "Bar & Grill <Live>"
+	tickets[2].Event.Name = "Bar & Grill <Live>"
+	err = client.SendDigestNotification(context.Background(), tickets, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	var message struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	err = json.Unmarshal(<-bodies, &message)
+	require.NoError(t, err)
+
+	require.Equal(t, "3 new ticket listing(s)", message.Text)
+	require.Len(t, message.Blocks, 2)
+	require.Equal(t, "header", message.Blocks[0].Type)
+	require.Equal(t, "section", message.Blocks[1].Type)
+
+	// Values in the digest should be escaped, and use slack markup
+	digest := message.Blocks[1].Text.Text
+	require.True(t, strings.HasPrefix(digest, "*Bar &amp; Grill &lt;Live&gt;*\n"))
+	require.Contains(t, digest, "<https://www.twickets.live/app/block/other,1|Buy>")
+}
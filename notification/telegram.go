@@ -1,43 +1,205 @@
 package notification
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// Prefixes of the data sent when alert buttons are pressed, followed by the event key
+const (
+	telegramSnoozeCallbackPrefix = "snooze:"
+	telegramStopCallbackPrefix   = "stop:"
+)
+
 type TelegramClient struct {
-	client *tgbotapi.BotAPI
-	chatId int
+	client    *tgbotapi.BotAPI
+	chats     []config.TelegramChat
+	parseMode string
+	format    Format
+
+	// Whether commands are handled, and so whether buttons that need handling can be added to alerts
+	commands bool
+
+	templates Templates
 }
 
-var _ Client = TelegramClient{}
+var _ DigestClient = TelegramClient{}
 
-func (c TelegramClient) SendTicketNotification(ticket twigots.TicketListing) error {
-	messageBody, err := RenderMessage(ticket, WithHeader(), WithFooter())
-	if err != nil {
-		return err
+func (c TelegramClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) error {
+	// The buy link is an inline button, so is not needed in the message
+	render := func(format Format) (string, error) {
+		return RenderMessage(ticket, WithHeader(), WithTemplate(c.templates.Message), WithFormat(format))
 	}
 
-	message := tgbotapi.NewMessage(int64(c.chatId), messageBody)
-	message.ParseMode = tgbotapi.ModeMarkdown
+	keyboard := c.keyboard(ticket, listingConfig)
+	return c.sendToChats(ctx, render, listingConfig.TelegramChatId, listingConfig.TelegramThreadId, &keyboard)
+}
+
+func (c TelegramClient) SendDigestNotification(
+	ctx context.Context,
+	tickets []twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) error {
+	render := func(format Format) (string, error) {
+		return RenderDigest(tickets, c.templates.Digest, format)
+	}
+	return c.sendToChats(ctx, render, listingConfig.TelegramChatId, listingConfig.TelegramThreadId, nil)
+}
 
-	_, err = c.client.Send(message)
+// sendToChats sends a message rendered in the format of the client to every chat, even if sending to one fails.
+// Messages can be sent to a forum topic of one of the chats, instead of the topic of the chat.
+func (c TelegramClient) sendToChats(
+	ctx context.Context,
+	render func(Format) (string, error),
+	overrideChatId, overrideThreadId int,
+	keyboard *tgbotapi.InlineKeyboardMarkup,
+) error {
+	messageBody, err := render(c.format)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	var errs []error
+	for _, chat := range c.chats {
+		threadId := chat.ThreadId
+		if overrideThreadId != 0 && chat.ChatId == overrideChatId {
+			threadId = overrideThreadId
+		}
+
+		err := c.sendMessage(ctx, chat.ChatId, threadId, messageBody, c.parseMode, keyboard)
+		if isTelegramParseError(err) {
+			// Telegram could not parse the formatting of the message e.g. due to a custom template,
+			// so send it again as plain text rather than not sending it at all
+			var plainMessageBody string
+			plainMessageBody, err = render(FormatPlain)
+			if err == nil {
+				err = c.sendMessage(ctx, chat.ChatId, threadId, plainMessageBody, "", keyboard)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send to chat %d: %w", chat.ChatId, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// keyboard gets the inline buttons to add to an alert.
+// Buttons to snooze and stop watching an event are only added if commands are handled,
+// as pressing them needs to be handled.
+func (c TelegramClient) keyboard(
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) tgbotapi.InlineKeyboardMarkup {
+	buttons := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonURL("Open", ticket.URL()),
+	}
+
+	if c.commands && listingConfig.Event != "" {
+		eventKey := telegramEventKey(listingConfig.Event)
+		buttons = append(
+			buttons,
+			tgbotapi.NewInlineKeyboardButtonData("Snooze 24h", telegramSnoozeCallbackPrefix+eventKey),
+			tgbotapi.NewInlineKeyboardButtonData("Stop watching", telegramStopCallbackPrefix+eventKey),
+		)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(buttons)
+}
+
+// sendMessage sends a message to a chat, and a forum topic of the chat if thread id is set.
+// Inline buttons are added to the message if the keyboard is not nil.
+// The telegram client does not support forum topics, so the request is made directly.
+func (c TelegramClient) sendMessage(
+	ctx context.Context,
+	chatId, threadId int,
+	text, parseMode string,
+	keyboard *tgbotapi.InlineKeyboardMarkup,
+) error {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", int64(chatId))
+	params.AddNonZero("message_thread_id", threadId)
+	params.AddNonEmpty("text", text)
+	params.AddNonEmpty("parse_mode", parseMode)
+	if keyboard != nil {
+		err := params.AddInterface("reply_markup", keyboard)
+		if err != nil {
+			return fmt.Errorf("failed to encode telegram buttons: %w", err)
+		}
+	}
+
+	return c.send(ctx, "sendMessage", params)
+}
+
+// send a request, returning early if the context is done.
+// The telegram client does not accept a context, so the request is made in a goroutine
+// that is left to finish in the background if the context is done first.
+func (c TelegramClient) send(ctx context.Context, endpoint string, params tgbotapi.Params) error {
+	result := make(chan error, 1) // Buffered, so the goroutine can always finish
+	go func() {
+		_, err := c.client.MakeRequest(endpoint, params)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func NewTelegramClient(conf config.TelegramConfig) (TelegramClient, error) {
-	client, err := tgbotapi.NewBotAPI(conf.Token)
+	client, err := newTelegramBot(conf)
 	if err != nil {
 		return TelegramClient{}, err
 	}
 
-	return TelegramClient{
-		client: client,
-		chatId: conf.ChatId,
-	}, nil
+	telegramClient := TelegramClient{
+		client:    client,
+		chats:     conf.AllChats(),
+		parseMode: tgbotapi.ModeHTML,
+		format:    FormatTelegramHTML,
+		commands:  conf.Commands,
+	}
+	if conf.ParseMode == config.TelegramParseModeMarkdownv2 {
+		telegramClient.parseMode = tgbotapi.ModeMarkdownV2
+		telegramClient.format = FormatTelegramMarkdownV2
+	}
+
+	return telegramClient, nil
+}
+
+// newTelegramBot creates a bot using the telegram bot api server in the config, or the official one if not set
+func newTelegramBot(conf config.TelegramConfig) (*tgbotapi.BotAPI, error) {
+	apiEndpoint := tgbotapi.APIEndpoint
+	if conf.ApiUrl != "" {
+		apiEndpoint = strings.TrimSuffix(conf.ApiUrl, "/") + "/bot%s/%s"
+	}
+	return tgbotapi.NewBotAPIWithAPIEndpoint(conf.Token, apiEndpoint)
+}
+
+// telegramEventKey gets a short key identifying a wanted event, ignoring case.
+// Event names can be too long to fit in the data of a button, which is limited to 64 bytes.
+func telegramEventKey(event string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(event))))
+	return hex.EncodeToString(hash[:8])
+}
+
+// isTelegramParseError checks whether an error is due to telegram not being able to parse the formatting of a message
+func isTelegramParseError(err error) bool {
+	var telegramErr *tgbotapi.Error
+	return errors.As(err, &telegramErr) && strings.Contains(telegramErr.Message, "can't parse entities")
 }
@@ -0,0 +1,418 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// Time telegram waits for updates before responding to a poll, in seconds
+	telegramPollTimeout = 30
+
+	// Time to wait before polling again after polling fails
+	telegramPollRetryDelay = 10 * time.Second
+
+	// Time an event is snoozed for when the snooze button of an alert is pressed
+	telegramSnoozeDuration = 24 * time.Hour
+)
+
+const telegramCommandsHelp = `Commands:
+/list - List the tickets being watched
+/add <event> [maxPrice] [numTickets] - Watch tickets for an event. Quote event names ending in a number e.g. /add "Blink 182" 50
+/remove <event> - Stop watching tickets for an event
+/pause - Pause notifications
+/resume - Resume notifications`
+
+// TelegramCommandHandler handles commands sent to a telegram bot, to manage the tickets being watched.
+// It also handles the buttons of alerts being pressed. Only commands and buttons from the configured chats are handled.
+//
+// Changes are saved to the config file, which is then reloaded, so they take effect without a restart.
+// Snoozed events are saved to the state store.
+type TelegramCommandHandler struct {
+	client     *tgbotapi.BotAPI
+	chatIds    []int64
+	configPath string
+	stateStore *store.Store
+}
+
+// Start polling for commands, and handling them. This blocks until the context is done.
+func (h TelegramCommandHandler) Start(ctx context.Context) error {
+	updateConfig := tgbotapi.UpdateConfig{
+		Timeout:        telegramPollTimeout,
+		AllowedUpdates: []string{"message", "callback_query"},
+	}
+
+	for {
+		// Polling does not accept a context, so updates are only checked for between polls
+		updates, err := h.client.GetUpdates(updateConfig)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			slog.Error(
+				"error polling for telegram commands",
+				"error", err,
+			)
+
+			select {
+			case <-time.After(telegramPollRetryDelay):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		for _, update := range updates {
+			updateConfig.Offset = update.UpdateID + 1
+			h.handleUpdate(update)
+		}
+	}
+}
+
+func (h TelegramCommandHandler) handleUpdate(update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		h.handleCallbackQuery(update.CallbackQuery)
+		return
+	}
+
+	message := update.Message
+	if message == nil || !message.IsCommand() {
+		return
+	}
+
+	// Ignore commands from other chats, so only the configured chats can manage tickets
+	if message.Chat == nil || !slices.Contains(h.chatIds, message.Chat.ID) {
+		slog.Warn(
+			"ignoring telegram command from unknown chat",
+			"command", message.Command(),
+		)
+		return
+	}
+
+	reply := h.handleCommand(message.Command(), message.CommandArguments())
+
+	replyMessage := tgbotapi.NewMessage(message.Chat.ID, reply)
+	replyMessage.ReplyToMessageID = message.MessageID
+	_, err := h.client.Send(replyMessage)
+	if err != nil {
+		slog.Error(
+			"error replying to telegram command",
+			"command", message.Command(),
+			"error", err,
+		)
+	}
+}
+
+// handleCallbackQuery handles a button of an alert being pressed, answering it with the result
+func (h TelegramCommandHandler) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+	// Ignore buttons pressed in other chats, so only the configured chats can manage tickets
+	if query.Message == nil || query.Message.Chat == nil || !slices.Contains(h.chatIds, query.Message.Chat.ID) {
+		slog.Warn(
+			"ignoring telegram button from unknown chat",
+			"data", query.Data,
+		)
+
+		// Still answer, so telegram stops showing the button as loading
+		h.answerCallbackQuery(query, "Error: tickets cannot be managed from this chat")
+		return
+	}
+
+	reply, err := h.handleButton(query.Data)
+	if err != nil {
+		reply = "Error: " + err.Error()
+	}
+	h.answerCallbackQuery(query, reply)
+}
+
+func (h TelegramCommandHandler) answerCallbackQuery(query *tgbotapi.CallbackQuery, reply string) {
+	_, err := h.client.Request(tgbotapi.NewCallback(query.ID, reply))
+	if err != nil {
+		slog.Error(
+			"error answering telegram button",
+			"data", query.Data,
+			"error", err,
+		)
+	}
+}
+
+// handleButton handles the data of a pressed button, returning the reply to send
+func (h TelegramCommandHandler) handleButton(data string) (string, error) {
+	if eventKey, ok := strings.CutPrefix(data, telegramSnoozeCallbackPrefix); ok {
+		return h.snooze(eventKey)
+	}
+	if eventKey, ok := strings.CutPrefix(data, telegramStopCallbackPrefix); ok {
+		return h.stopWatching(eventKey)
+	}
+	return "", fmt.Errorf("button '%s' is not supported", data)
+}
+
+func (h TelegramCommandHandler) snooze(eventKey string) (string, error) {
+	event, err := h.eventFromKey(eventKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = h.stateStore.SnoozeEvent(event, time.Now().Add(telegramSnoozeDuration))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Snoozed notifications for '%s' for 24 hours", event), nil
+}
+
+func (h TelegramCommandHandler) stopWatching(eventKey string) (string, error) {
+	event, err := h.eventFromKey(eventKey)
+	if err != nil {
+		return "", err
+	}
+
+	return h.remove(event)
+}
+
+// eventFromKey gets the wanted event with a key, as used in the data of alert buttons
+func (h TelegramCommandHandler) eventFromKey(eventKey string) (string, error) {
+	conf, err := config.Load(h.configPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ticketConfig := range conf.TicketConfigs {
+		if telegramEventKey(ticketConfig.Event) == eventKey {
+			return ticketConfig.Event, nil
+		}
+	}
+
+	return "", errors.New("tickets for this event are no longer being watched")
+}
+
+// handleCommand handles a command, returning the reply to send
+func (h TelegramCommandHandler) handleCommand(command, arguments string) string {
+	var reply string
+	var err error
+	switch command {
+	case "list":
+		reply, err = h.list()
+	case "add":
+		reply, err = h.add(arguments)
+	case "remove":
+		reply, err = h.remove(arguments)
+	case "pause":
+		reply, err = h.setPaused(true)
+	case "resume":
+		reply, err = h.setPaused(false)
+	case "start", "help":
+		reply = telegramCommandsHelp
+	default:
+		reply = fmt.Sprintf("Unknown command /%s\n\n%s", command, telegramCommandsHelp)
+	}
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	return reply
+}
+
+func (h TelegramCommandHandler) list() (string, error) {
+	conf, err := config.Load(h.configPath)
+	if err != nil {
+		return "", err
+	}
+
+	listingConfigs := conf.CombinedTicketListingConfigs()
+	if len(listingConfigs) == 0 {
+		return "No tickets are being watched", nil
+	}
+
+	var builder strings.Builder
+	if conf.Paused {
+		builder.WriteString("Notifications are paused\n\n")
+	}
+	fmt.Fprintf(&builder, "Watching tickets for %d event(s):", len(listingConfigs))
+	for _, listingConfig := range listingConfigs {
+		builder.WriteString("\n\n")
+		builder.WriteString(strings.TrimSpace(config.FormatTicketListingConfig(listingConfig)))
+	}
+
+	return builder.String(), nil
+}
+
+func (h TelegramCommandHandler) add(arguments string) (string, error) {
+	listingConfig, err := parseTelegramAddArguments(arguments)
+	if err != nil {
+		return "", err
+	}
+
+	err = h.updateConfig(func(conf *config.Config) error {
+		if ticketConfigIndex(conf.TicketConfigs, listingConfig.Event) != -1 {
+			return fmt.Errorf("tickets for '%s' are already being watched", listingConfig.Event)
+		}
+
+		conf.TicketConfigs = append(conf.TicketConfigs, listingConfig)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Watching tickets for '%s'", listingConfig.Event), nil
+}
+
+func (h TelegramCommandHandler) remove(arguments string) (string, error) {
+	event := strings.Trim(strings.TrimSpace(arguments), `"`)
+	if event == "" {
+		return "", errors.New("event must be set e.g. /remove Taylor Swift")
+	}
+
+	err := h.updateConfig(func(conf *config.Config) error {
+		index := ticketConfigIndex(conf.TicketConfigs, event)
+		if index == -1 {
+			return fmt.Errorf("tickets for '%s' are not being watched", event)
+		}
+
+		conf.TicketConfigs = slices.Delete(conf.TicketConfigs, index, index+1)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Stopped watching tickets for '%s'", event), nil
+}
+
+func (h TelegramCommandHandler) setPaused(paused bool) (string, error) {
+	err := h.updateConfig(func(conf *config.Config) error {
+		conf.Paused = paused
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if paused {
+		return "Notifications paused. Use /resume to resume them", nil
+	}
+	return "Notifications resumed", nil
+}
+
+// updateConfig loads the config file, updates it, and saves it if it is still valid
+func (h TelegramCommandHandler) updateConfig(update func(*config.Config) error) error {
+	conf, err := config.Load(h.configPath)
+	if err != nil {
+		return err
+	}
+
+	err = update(&conf)
+	if err != nil {
+		return err
+	}
+
+	err = conf.Validate()
+	if err != nil {
+		return err
+	}
+
+	return config.Save(conf, h.configPath)
+}
+
+// ticketConfigIndex gets the index of the ticket config for an event, ignoring case.
+// If there is no ticket config for the event, -1 is returned.
+func ticketConfigIndex(ticketConfigs []config.TicketListingConfig, event string) int {
+	return slices.IndexFunc(ticketConfigs, func(ticketConfig config.TicketListingConfig) bool {
+		return strings.EqualFold(ticketConfig.Event, event)
+	})
+}
+
+// parseTelegramAddArguments parses the arguments of the add command: <event> [maxPrice] [numTickets].
+// Numbers at the end of the arguments are the optional settings, unless the event is quoted.
+func parseTelegramAddArguments(arguments string) (config.TicketListingConfig, error) {
+	arguments = strings.TrimSpace(arguments)
+
+	var event string
+	var settings []string
+	if rest, quoted := strings.CutPrefix(arguments, `"`); quoted {
+		var found bool
+		event, rest, found = strings.Cut(rest, `"`)
+		if !found {
+			return config.TicketListingConfig{}, errors.New("event is missing a closing quote")
+		}
+		settings = strings.Fields(rest)
+	} else {
+		fields := strings.Fields(arguments)
+
+		// Settings are the numbers at the end, of which there are at most 2
+		numSettings := 0
+		for numSettings < 2 && numSettings < len(fields) && isTelegramNumber(fields[len(fields)-numSettings-1]) {
+			numSettings++
+		}
+		event = strings.Join(fields[:len(fields)-numSettings], " ")
+		settings = fields[len(fields)-numSettings:]
+	}
+
+	event = strings.TrimSpace(event)
+	if event == "" {
+		return config.TicketListingConfig{}, errors.New("event must be set e.g. /add Taylor Swift 100 2")
+	}
+	if len(settings) > 2 {
+		return config.TicketListingConfig{}, errors.New("only max price and number of tickets can be set")
+	}
+
+	listingConfig := config.TicketListingConfig{Event: event}
+
+	if len(settings) > 0 {
+		maxPrice, err := strconv.ParseFloat(strings.TrimPrefix(settings[0], "£"), 64)
+		if err != nil || maxPrice <= 0 {
+			return config.TicketListingConfig{}, fmt.Errorf("max price '%s' is not a positive number", settings[0])
+		}
+		listingConfig.MaxTicketPriceInclFee = &maxPrice
+	}
+
+	if len(settings) > 1 {
+		numTickets, err := strconv.Atoi(settings[1])
+		if err != nil || numTickets <= 0 {
+			return config.TicketListingConfig{}, fmt.Errorf("number of tickets '%s' is not a positive whole number", settings[1])
+		}
+		listingConfig.NumTickets = &numTickets
+	}
+
+	return listingConfig, nil
+}
+
+// isTelegramNumber checks whether a command argument is a number, allowing prices to start with £
+func isTelegramNumber(argument string) bool {
+	_, err := strconv.ParseFloat(strings.TrimPrefix(argument, "£"), 64)
+	return err == nil
+}
+
+func NewTelegramCommandHandler(
+	conf config.TelegramConfig,
+	configPath string,
+	stateStore *store.Store,
+) (TelegramCommandHandler, error) {
+	client, err := newTelegramBot(conf)
+	if err != nil {
+		return TelegramCommandHandler{}, err
+	}
+
+	chats := conf.AllChats()
+	chatIds := make([]int64, 0, len(chats))
+	for _, chat := range chats {
+		chatIds = append(chatIds, int64(chat.ChatId))
+	}
+
+	return TelegramCommandHandler{
+		client:     client,
+		chatIds:    chatIds,
+		configPath: configPath,
+		stateStore: stateStore,
+	}, nil
+}
@@ -0,0 +1,292 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTelegramToken  = "123:test"
+	testTelegramChatId = 1234
+)
+
+const testTelegramConfig = `
+apiKey: test
+country: GB
+global: {}
+tickets:
+  - event: Taylor Swift
+`
+
+// fakeTelegramServer is a fake telegram bot api server.
+// Updates added to it are returned when polled, and sent messages and answers are recorded.
+type fakeTelegramServer struct {
+	mutex    sync.Mutex
+	updates  []tgbotapi.Update
+	messages chan string
+	sent     chan url.Values // Forms of sent messages
+
+	// Whether to reject messages with formatting, like telegram does when it cannot parse them
+	rejectFormatting bool
+}
+
+func newFakeTelegramServer(t *testing.T) (*fakeTelegramServer, string) {
+	fake := &fakeTelegramServer{
+		messages: make(chan string, 10),
+		sent:     make(chan url.Values, 10),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		var result any
+		switch strings.TrimPrefix(r.URL.Path, "/bot"+testTelegramToken+"/") {
+		case "getMe":
+			result = tgbotapi.User{ID: 1, IsBot: true, FirstName: "Test", UserName: "test_bot"}
+		case "getUpdates":
+			result = fake.popUpdates()
+		case "sendMessage":
+			if fake.rejectFormatting && r.Form.Get("parse_mode") != "" {
+				w.Header().Set("Content-Type", "application/json")
+				err := json.NewEncoder(w).Encode(tgbotapi.APIResponse{
+					Ok:          false,
+					ErrorCode:   http.StatusBadRequest,
+					Description: "Bad Request: can't parse entities: can't find end of the entity",
+				})
+				require.NoError(t, err)
+				return
+			}
+			fake.messages <- r.Form.Get("text")
+			select {
+			case fake.sent <- r.Form:
+			default:
+			}
+			result = tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: testTelegramChatId}}
+		case "answerCallbackQuery":
+			fake.messages <- r.Form.Get("text")
+			result = true
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		resultBytes, err := json.Marshal(result)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: resultBytes})
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server.URL
+}
+
+// addCommand adds an update with a command sent from a chat
+func (f *fakeTelegramServer) addCommand(chatId int64, text string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	command, _, _ := strings.Cut(text, " ")
+	f.updates = append(f.updates, tgbotapi.Update{
+		UpdateID: len(f.updates) + 1,
+		Message: &tgbotapi.Message{
+			MessageID: len(f.updates) + 1,
+			Chat:      &tgbotapi.Chat{ID: chatId},
+			Text:      text,
+			Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+		},
+	})
+}
+
+// popUpdates gets the updates that have not been polled yet, waiting a little if there are none
+func (f *fakeTelegramServer) popUpdates() []tgbotapi.Update {
+	f.mutex.Lock()
+	updates := f.updates
+	f.updates = nil
+	f.mutex.Unlock()
+
+	if len(updates) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return updates
+}
+
+// addButtonPress adds an update with a button of a message being pressed in a chat
+func (f *fakeTelegramServer) addButtonPress(chatId int64, data string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.updates = append(f.updates, tgbotapi.Update{
+		UpdateID: len(f.updates) + 1,
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:      strconv.Itoa(len(f.updates) + 1),
+			Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: chatId}},
+			Data:    data,
+		},
+	})
+}
+
+func (f *fakeTelegramServer) sendCommand(t *testing.T, text string) string {
+	f.addCommand(testTelegramChatId, text)
+	return f.waitForMessage(t)
+}
+
+func (f *fakeTelegramServer) pressButton(t *testing.T, data string) string {
+	f.addButtonPress(testTelegramChatId, data)
+	return f.waitForMessage(t)
+}
+
+func (f *fakeTelegramServer) waitForMessage(t *testing.T) string {
+	select {
+	case message := <-f.messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+// startTestTelegramCommandHandler starts a command handler using a fake server, and a test config file and store
+func startTestTelegramCommandHandler(t *testing.T) (*fakeTelegramServer, config.TelegramConfig, string, *store.Store) {
+	fake, serverUrl := newFakeTelegramServer(t)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configPath, []byte(testTelegramConfig), 0o600)
+	require.NoError(t, err)
+
+	stateStore, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stateStore.Close() })
+
+	telegramConfig := config.TelegramConfig{
+		Token:    testTelegramToken,
+		ChatId:   testTelegramChatId,
+		ApiUrl:   serverUrl,
+		Commands: true,
+	}
+
+	handler, err := notification.NewTelegramCommandHandler(telegramConfig, configPath, stateStore)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = handler.Start(ctx) }()
+
+	return fake, telegramConfig, configPath, stateStore
+}
+
+func TestTelegramCommands(t *testing.T) {
+	fake, _, configPath, _ := startTestTelegramCommandHandler(t)
+
+	// Add
+	reply := fake.sendCommand(t, `/add "Blink 182" £50 2`)
+	require.Equal(t, "Watching tickets for 'Blink 182'", reply)
+
+	reply = fake.sendCommand(t, "/add taylor swift 100")
+	require.Equal(t, "Error: tickets for 'taylor swift' are already being watched", reply)
+
+	conf, err := config.Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, conf.TicketConfigs, 2)
+	require.Equal(t, "Blink 182", conf.TicketConfigs[1].Event)
+	require.InDelta(t, 50.0, *conf.TicketConfigs[1].MaxTicketPriceInclFee, 0.001)
+	require.Equal(t, 2, *conf.TicketConfigs[1].NumTickets)
+
+	// List
+	reply = fake.sendCommand(t, "/list")
+	require.Contains(t, reply, "Watching tickets for 2 event(s)")
+	require.Contains(t, reply, "Event: Taylor Swift")
+	require.Contains(t, reply, "Event: Blink 182")
+	require.Contains(t, reply, "Max Ticket Price: £50.00")
+
+	// Remove
+	reply = fake.sendCommand(t, "/remove taylor swift")
+	require.Equal(t, "Stopped watching tickets for 'taylor swift'", reply)
+
+	conf, err = config.Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, conf.TicketConfigs, 1)
+
+	// Pause
+	reply = fake.sendCommand(t, "/pause")
+	require.Contains(t, reply, "Notifications paused")
+
+	conf, err = config.Load(configPath)
+	require.NoError(t, err)
+	require.True(t, conf.Paused)
+
+	// Commands from other chats should be ignored, so the next reply is to resume
+	fake.addCommand(5678, "/pause")
+	reply = fake.sendCommand(t, "/resume")
+	require.Equal(t, "Notifications resumed", reply)
+
+	conf, err = config.Load(configPath)
+	require.NoError(t, err)
+	require.False(t, conf.Paused)
+}
+
+func TestTelegramAlertButtons(t *testing.T) {
+	fake, telegramConfig, configPath, stateStore := startTestTelegramCommandHandler(t)
+
+	client, err := notification.NewTelegramClient(telegramConfig)
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	listingConfig := config.TicketListingConfig{Event: "Taylor Swift"}
+	err = client.SendTicketNotification(context.Background(), ticket, listingConfig)
+	require.NoError(t, err)
+
+	message := fake.waitForMessage(t)
+	require.NotContains(t, message, "[Buy Link]")
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	err = json.Unmarshal([]byte((<-fake.sent).Get("reply_markup")), &keyboard)
+	require.NoError(t, err)
+	require.Len(t, keyboard.InlineKeyboard, 1)
+
+	buttons := keyboard.InlineKeyboard[0]
+	require.Len(t, buttons, 3)
+	require.Equal(t, "Open", buttons[0].Text)
+	require.Equal(t, ticket.URL(), *buttons[0].URL)
+	require.Equal(t, "Snooze 24h", buttons[1].Text)
+	require.Equal(t, "Stop watching", buttons[2].Text)
+
+	// Snooze
+	reply := fake.pressButton(t, *buttons[1].CallbackData)
+	require.Equal(t, "Snoozed notifications for 'Taylor Swift' for 24 hours", reply)
+
+	snoozed, err := stateStore.IsEventSnoozed("Taylor Swift", time.Now())
+	require.NoError(t, err)
+	require.True(t, snoozed)
+
+	// Buttons pressed in other chats should be answered, but not handled
+	fake.addButtonPress(5678, *buttons[2].CallbackData)
+	require.Equal(t, "Error: tickets cannot be managed from this chat", fake.waitForMessage(t))
+
+	reply = fake.pressButton(t, *buttons[2].CallbackData)
+	require.Equal(t, "Stopped watching tickets for 'Taylor Swift'", reply)
+
+	conf, err := config.Load(configPath)
+	require.NoError(t, err)
+	require.Empty(t, conf.TicketConfigs)
+
+	reply = fake.pressButton(t, *buttons[2].CallbackData)
+	require.Equal(t, "Error: tickets for this event are no longer being watched", reply)
+}
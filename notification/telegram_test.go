@@ -1,10 +1,14 @@
 package notification_test
 
 import (
+	"context"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twitchets/config"
 	"github.com/ahobsonsayers/twitchets/notification"
 	"github.com/ahobsonsayers/twitchets/test"
@@ -33,6 +37,88 @@ func TestTelegramSendTicketMessage(t *testing.T) {
 	require.NoError(t, err)
 
 	ticket := testNotificationTicket()
-	err = client.SendTicketNotification(ticket)
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
 	require.NoError(t, err)
 }
+
+func TestTelegramSendTicketMessageAsPlainText(t *testing.T) {
+	fake, serverUrl := newFakeTelegramServer(t)
+	fake.rejectFormatting = true
+
+	client, err := notification.NewTelegramClient(config.TelegramConfig{
+		Token:     testTelegramToken,
+		ChatId:    testTelegramChatId,
+		ApiUrl:    serverUrl,
+		ParseMode: config.TelegramParseModeMarkdownv2,
+	})
+	require.NoError(t, err)
+
+	ticket := testFormatTicket()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = client.SendTicketNotification(ctx, ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	// Telegram could not parse the message, so it should have been sent again as plain text
+	message := fake.waitForMessage(t)
+	require.True(t, strings.HasPrefix(message, ticket.Event.Name+"\n"))
+	require.Contains(t, message, "Bar & Grill <Upstairs>, Test Location")
+}
+
+func TestTelegramSendTicketMessageToChats(t *testing.T) {
+	fake, serverUrl := newFakeTelegramServer(t)
+
+	client, err := notification.NewTelegramClient(config.TelegramConfig{
+		Token:  testTelegramToken,
+		ChatId: testTelegramChatId,
+		Chats: []config.TelegramChat{
+			{ChatId: -1001234, ThreadId: 5},
+			{ChatId: -1005678},
+		},
+		ApiUrl: serverUrl,
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// sentThreadIds gets the thread each chat was sent a message in
+	sentThreadIds := func() map[string]string {
+		threadIds := map[string]string{}
+		for range 3 {
+			form := <-fake.sent
+			<-fake.messages
+			threadIds[form.Get("chat_id")] = form.Get("message_thread_id")
+		}
+		return threadIds
+	}
+
+	// Thread of the chosen chat should be overridden by the thread of the tickets, but not other chats
+	listingConfig := config.TicketListingConfig{Event: "Test Event", TelegramThreadId: 10, TelegramChatId: -1005678}
+	err = client.SendTicketNotification(ctx, ticket, listingConfig)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]string{strconv.Itoa(testTelegramChatId): "", "-1001234": "5", "-1005678": "10"},
+		sentThreadIds(),
+	)
+
+	// Digests of the tickets should be sent to the same threads
+	err = client.SendDigestNotification(ctx, []twigots.TicketListing{ticket, ticket}, listingConfig)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]string{strconv.Itoa(testTelegramChatId): "", "-1001234": "5", "-1005678": "10"},
+		sentThreadIds(),
+	)
+
+	// Without an override, the thread of the chat should be used
+	err = client.SendTicketNotification(ctx, ticket, config.TicketListingConfig{Event: "Test Event"})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string]string{strconv.Itoa(testTelegramChatId): "", "-1001234": "5", "-1005678": ""},
+		sentThreadIds(),
+	)
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+// Templates are custom templates used to render notifications.
+// Message and title templates are rendered with MessageTemplateData,
+// and digest templates with DigestTemplateData. Any template that is nil uses the default.
+type Templates struct {
+	Message *template.Template
+	Title   *template.Template
+	Digest  *template.Template
+}
+
+// NewTemplates parses the custom templates of a notifier.
+// Templates are checked by rendering them with an empty ticket listing (or digest),
+// so templates using data that does not exist fail now rather than when a notification is sent.
+func NewTemplates(notifier config.NotifierConfig) (Templates, error) {
+	var templates Templates
+
+	messageTemplate, err := notifier.MessageTemplate()
+	if err != nil {
+		return Templates{}, err
+	}
+
+	if messageTemplate != "" {
+		templates.Message, err = parseTemplate("message", messageTemplate, newMessageTemplateData(twigots.TicketListing{}))
+		if err != nil {
+			return Templates{}, fmt.Errorf("template is not valid: %w", err)
+		}
+	}
+
+	if notifier.TitleTemplate != "" {
+		templates.Title, err = parseTemplate("title", notifier.TitleTemplate, newMessageTemplateData(twigots.TicketListing{}))
+		if err != nil {
+			return Templates{}, fmt.Errorf("title template is not valid: %w", err)
+		}
+	}
+
+	if notifier.DigestTemplate != "" {
+		templates.Digest, err = parseTemplate("digest", notifier.DigestTemplate, newDigestTemplateData(nil))
+		if err != nil {
+			return Templates{}, fmt.Errorf("digest template is not valid: %w", err)
+		}
+	}
+
+	return templates, nil
+}
+
+// parseTemplate parses a custom template. Values output by the template are escaped for the format it is rendered in,
+// but its text is not, as it is written by the user using the markup of the notifier.
+// The template is checked by rendering it with data.
+func parseTemplate(name, text string, data any) (*template.Template, error) {
+	tmpl, err := parseFormattedTemplate(name, text, false)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = executeFormattedTemplate(tmpl, FormatPlain, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// RenderTitle renders the title of a notification.
+// If the template is nil, the event name is used.
+func RenderTitle(ticket twigots.TicketListing, tmpl *template.Template) (string, error) {
+	if tmpl == nil {
+		return ticket.Event.Name, nil
+	}
+
+	templateData := newMessageTemplateData(ticket)
+	templateData.Event = ticket.Event.Name
+	templateData.Link = ticket.URL()
+
+	// Titles are not displayed with markup
+	title, err := executeFormattedTemplate(tmpl, FormatPlain, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to render notification title template: %w", err)
+	}
+
+	return strings.TrimSpace(title), nil
+}
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+)
+
+const (
+	// Version of the webhook payload.
+	// This must be incremented whenever a breaking change is made to the payload.
+	WebhookPayloadVersion = 1
+
+	// Header containing the signature of a webhook request body
+	WebhookSignatureHeader = "X-Twitchets-Signature"
+)
+
+// WebhookPayload is the JSON document sent to webhooks
+type WebhookPayload struct {
+	Version       int                        `json:"version"`
+	Title         string                     `json:"title"`
+	Message       string                     `json:"message"`
+	Link          string                     `json:"link"`
+	Listing       twigots.TicketListing      `json:"listing"`
+	ListingConfig config.TicketListingConfig `json:"listingConfig"`
+}
+
+type WebhookClient struct {
+	url          string
+	headers      map[string]string
+	secret       string
+	bodyTemplate *template.Template
+
+	templates Templates
+	client    *http.Client
+}
+
+var _ Client = WebhookClient{}
+
+func (c WebhookClient) SendTicketNotification(
+	ctx context.Context,
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+) error {
+	payload, err := newWebhookPayload(ticket, listingConfig, c.templates)
+	if err != nil {
+		return err
+	}
+
+	body, contentType, err := c.renderBody(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", contentType)
+	for key, value := range c.headers {
+		request.Header.Set(key, value)
+	}
+	if c.secret != "" {
+		request.Header.Set(WebhookSignatureHeader, WebhookSignature(body, c.secret))
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", response.Status)
+	}
+
+	return nil
+}
+
+// renderBody renders the request body and its content type.
+// This is the JSON payload, unless there is a body template.
+func (c WebhookClient) renderBody(payload WebhookPayload) ([]byte, string, error) {
+	if c.bodyTemplate == nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+		return body, "application/json", nil
+	}
+
+	var buffer bytes.Buffer
+	err := c.bodyTemplate.Execute(&buffer, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render webhook body template: %w", err)
+	}
+	return buffer.Bytes(), "text/plain; charset=utf-8", nil
+}
+
+// newWebhookPayload creates the payload for a ticket listing, rendering the title and message using templates
+func newWebhookPayload(
+	ticket twigots.TicketListing,
+	listingConfig config.TicketListingConfig,
+	templates Templates,
+) (WebhookPayload, error) {
+	notificationMessage, err := RenderMessage(ticket, WithHeader(), WithFooter(), WithTemplate(templates.Message))
+	if err != nil {
+		return WebhookPayload{}, err
+	}
+
+	notificationTitle, err := RenderTitle(ticket, templates.Title)
+	if err != nil {
+		return WebhookPayload{}, err
+	}
+
+	return WebhookPayload{
+		Version:       WebhookPayloadVersion,
+		Title:         notificationTitle,
+		Message:       notificationMessage,
+		Link:          ticket.URL(),
+		Listing:       ticket,
+		ListingConfig: listingConfig,
+	}, nil
+}
+
+func NewWebhookClient(conf config.WebhookConfig) (WebhookClient, error) {
+	var bodyTemplate *template.Template
+	if conf.BodyTemplate != "" {
+		var err error
+		bodyTemplate, err = template.New("body").Parse(conf.BodyTemplate)
+		if err != nil {
+			return WebhookClient{}, fmt.Errorf("webhook body template is not valid: %w", err)
+		}
+
+		err = bodyTemplate.Execute(io.Discard, WebhookPayload{})
+		if err != nil {
+			return WebhookClient{}, fmt.Errorf("webhook body template is not valid: %w", err)
+		}
+	}
+
+	return WebhookClient{
+		url:          conf.Url,
+		headers:      conf.Headers,
+		secret:       conf.Secret,
+		bodyTemplate: bodyTemplate,
+
+		client: http.DefaultClient,
+	}, nil
+}
+
+// WebhookSignature gets the signature of a webhook request body.
+// This is the HMAC-SHA256 of the body using the secret, in hex, prefixed with "sha256=".
+func WebhookSignature(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookReceiver starts a server that records the requests it receives
+func newWebhookReceiver(t *testing.T, statusCode int) (*httptest.Server, <-chan webhookRequest) {
+	requests := make(chan webhookRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		requests <- webhookRequest{header: r.Header, body: body}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestWebhookSendTicketMessage(t *testing.T) {
+	server, requests := newWebhookReceiver(t, http.StatusNoContent)
+
+	client, err := notification.NewWebhookClient(config.WebhookConfig{
+		Url:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer test"},
+		Secret:  "secret",
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	listingConfig := config.TicketListingConfig{Event: "Test Event"}
+	err = client.SendTicketNotification(context.Background(), ticket, listingConfig)
+	require.NoError(t, err)
+
+	request := <-requests
+	require.Equal(t, "application/json", request.header.Get("Content-Type"))
+	require.Equal(t, "Bearer test", request.header.Get("Authorization"))
+	require.Equal(
+		t,
+		notification.WebhookSignature(request.body, "secret"),
+		request.header.Get(notification.WebhookSignatureHeader),
+	)
+
+	// Listings cannot be unmarshalled after being marshalled, so only check the listing id
+	var payload struct {
+		Version       int                        `json:"version"`
+		Title         string                     `json:"title"`
+		Message       string                     `json:"message"`
+		Link          string                     `json:"link"`
+		Listing       map[string]any             `json:"listing"`
+		ListingConfig config.TicketListingConfig `json:"listingConfig"`
+	}
+	err = json.Unmarshal(request.body, &payload)
+	require.NoError(t, err)
+
+	require.Equal(t, notification.WebhookPayloadVersion, payload.Version)
+	require.Equal(t, "Test Event", payload.Title)
+	require.Contains(t, payload.Message, "Test Event")
+	require.Equal(t, ticket.URL(), payload.Link)
+	require.Equal(t, ticket.Id, payload.Listing["blockId"])
+	require.Equal(t, listingConfig, payload.ListingConfig)
+}
+
+func TestWebhookSendTicketMessageWithBodyTemplate(t *testing.T) {
+	server, requests := newWebhookReceiver(t, http.StatusOK)
+
+	client, err := notification.NewWebhookClient(config.WebhookConfig{
+		Url:          server.URL,
+		BodyTemplate: "{{ .Title }} - {{ .Link }}",
+	})
+	require.NoError(t, err)
+
+	ticket := testNotificationTicket()
+	err = client.SendTicketNotification(context.Background(), ticket, config.TicketListingConfig{})
+	require.NoError(t, err)
+
+	request := <-requests
+	require.Equal(t, "Test Event - "+ticket.URL(), string(request.body))
+	require.Empty(t, request.header.Get(notification.WebhookSignatureHeader))
+}
+
+func TestWebhookSendTicketMessageErrorStatus(t *testing.T) {
+	server, _ := newWebhookReceiver(t, http.StatusInternalServerError)
+
+	client, err := notification.NewWebhookClient(config.WebhookConfig{Url: server.URL})
+	require.NoError(t, err)
+
+	err = client.SendTicketNotification(context.Background(), testNotificationTicket(), config.TicketListingConfig{})
+	require.ErrorContains(t, err, "500")
+}
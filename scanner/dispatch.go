@@ -0,0 +1,351 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/store"
+	"github.com/samber/lo"
+)
+
+const (
+	// Number of workers sending notifications
+	numDispatchWorkers = 4
+
+	// Number of notifications waiting to be sent.
+	// If the queue is full, matching will wait until there is space.
+	dispatchQueueSize = 100
+
+	// Maximum time to wait for a notification to be sent, if the notifier does not set its own.
+	// Each notification gets its own timeout, so a stuck notification service
+	// cannot stop other notifications from being sent.
+	defaultNotificationTimeout = config.DefaultNotifierTimeout
+
+	// Maximum time to wait for queued and in-flight notifications to be sent when stopping.
+	// Any notifications still being sent after this are cancelled.
+	shutdownGracePeriod = 10 * time.Second
+
+	// Maximum number of attempts to send a notification before it is moved to the dead letters
+	maxDeliveryAttempts = 8
+
+	// Time to wait before retrying a failed notification.
+	// This doubles after every failed attempt, up to the max.
+	initialRetryBackoff = 30 * time.Second
+	maxRetryBackoff     = 30 * time.Minute
+)
+
+// notificationJob is a notification waiting to be sent
+type notificationJob struct {
+	notifier string
+	client   notification.Client
+
+	// Config the client is from, which is used until the notification has been sent,
+	// so the client is not closed if the config is replaced.
+	// Not set if the client is not from a scanner config.
+	conf *scannerConfig
+
+	listing       twigots.TicketListing
+	listingConfig config.TicketListingConfig
+
+	// Maximum time to wait for the notification to be sent.
+	// Default is used if not set.
+	timeout time.Duration
+
+	// Number of previous failed attempts to send the notification
+	attempts int
+
+	// Whether the notification is a pending delivery being retried.
+	// Resent dead letters are retried with no previous attempts.
+	retry bool
+
+	// Whether the notification was held. Held deliveries are only removed once the notification
+	// has been sent, or has failed and been stored to be retried.
+	held bool
+
+	// Held notifications to send as one digest, instead of the listing.
+	// Digests are only sent by clients that are digest clients.
+	digest []store.Delivery
+}
+
+func (j notificationJob) deliveryId() string {
+	return store.DeliveryId(j.listing.Id, j.notifier, j.listingConfig.Event)
+}
+
+// sendTimeout gets the maximum time to wait for the notification to be sent
+func (j notificationJob) sendTimeout() time.Duration {
+	if j.timeout > 0 {
+		return j.timeout
+	}
+	return defaultNotificationTimeout
+}
+
+// dispatcher sends notifications using a pool of workers reading from a bounded queue
+type dispatcher struct {
+	jobs       chan notificationJob
+	workersWg  sync.WaitGroup
+	stateStore *store.Store
+
+	// Ids of failed deliveries that are currently being retried,
+	// so they are not retried again until the current attempt has finished
+	retrying sync.Map
+
+	// Ids of held deliveries that are currently being sent,
+	// so they are not released again until sending has finished
+	releasing sync.Map
+
+	// Maximum time to wait for notifications to be sent when stopping
+	gracePeriod time.Duration
+
+	// Context of notifications being sent.
+	// This is separate to the scanner context, so in-flight notifications
+	// can finish when the scanner is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newDispatcher(stateStore *store.Store) *dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &dispatcher{
+		jobs:        make(chan notificationJob, dispatchQueueSize),
+		stateStore:  stateStore,
+		gracePeriod: shutdownGracePeriod,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// start the dispatcher workers
+func (d *dispatcher) start() {
+	for range numDispatchWorkers {
+		d.workersWg.Add(1)
+		go func() {
+			defer d.workersWg.Done()
+			for job := range d.jobs {
+				d.send(job)
+			}
+		}()
+	}
+}
+
+// stop the dispatcher, waiting until all queued notifications have been sent
+// or the shutdown grace period has passed, after which they are cancelled.
+// No notifications can be dispatched once stopped.
+func (d *dispatcher) stop() {
+	close(d.jobs)
+
+	stopped := make(chan struct{})
+	go func() {
+		d.workersWg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(d.gracePeriod):
+		slog.Warn("Timed out waiting for notifications to be sent. Cancelling remaining notifications.")
+		d.cancel()
+		<-stopped
+	}
+
+	d.cancel()
+}
+
+// dispatch queues a notification to be sent.
+// The config of the notification must not have been released yet.
+// This blocks if the queue is full.
+func (d *dispatcher) dispatch(job notificationJob) {
+	if job.conf != nil {
+		job.conf.users.Add(1)
+	}
+	d.jobs <- job
+}
+
+func (d *dispatcher) send(job notificationJob) {
+	defer job.conf.release()
+
+	if len(job.digest) != 0 {
+		d.sendDigest(job)
+		return
+	}
+
+	if job.retry {
+		defer d.retrying.Delete(job.deliveryId())
+	}
+	if job.held {
+		defer d.finishHeld(job.deliveryId())
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, job.sendTimeout())
+	defer cancel()
+
+	err := job.client.SendTicketNotification(ctx, job.listing, job.listingConfig)
+	if err != nil {
+		d.handleFailedDelivery(job, err)
+		return
+	}
+
+	// Remove the delivery if it was being retried
+	if job.retry {
+		slog.Info(
+			"Sent notification after retrying.",
+			"notifier", job.notifier,
+			"listingId", job.listing.Id,
+			"attempts", job.attempts+1,
+		)
+
+		err := d.stateStore.DeletePendingDelivery(job.deliveryId())
+		if err != nil {
+			slog.Error(err.Error())
+		}
+	}
+}
+
+// handleFailedDelivery stores a failed delivery so it can be retried later.
+// If the delivery has run out of attempts, it is moved to the dead letters instead.
+func (d *dispatcher) handleFailedDelivery(job notificationJob, sendErr error) {
+	now := time.Now()
+	delivery := store.Delivery{
+		Notifier:      job.notifier,
+		Listing:       job.listing,
+		ListingConfig: job.listingConfig,
+		Attempts:      job.attempts + 1,
+		LastError:     sendErr.Error(),
+		LastAttemptAt: now,
+	}
+
+	if delivery.Attempts >= maxDeliveryAttempts {
+		slog.Error(
+			"Failed to send notification. No attempts left, so moving to dead letters.",
+			"notifier", job.notifier,
+			"listingId", job.listing.Id,
+			"attempts", delivery.Attempts,
+			"err", sendErr,
+		)
+
+		err := d.stateStore.SetDeadLetterDelivery(delivery)
+		if err != nil {
+			slog.Error(err.Error())
+		}
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(retryBackoff(delivery.Attempts))
+	slog.Error(
+		"Failed to send notification. Will retry.",
+		"notifier", job.notifier,
+		"listingId", job.listing.Id,
+		"attempts", delivery.Attempts,
+		"nextAttemptAt", delivery.NextAttemptAt,
+		"err", sendErr,
+	)
+
+	err := d.stateStore.SetPendingDelivery(delivery)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// retry dispatches failed deliveries that are due to be retried, using the clients of a config.
+// Deliveries for notifiers that are no longer configured are moved to the dead letters,
+// and those for events that have been snoozed or are no longer watched are dropped.
+func (d *dispatcher) retry(conf *scannerConfig) {
+	deliveries, err := d.stateStore.PendingDeliveries()
+	if err != nil {
+		slog.Error(err.Error())
+		return
+	}
+
+	now := time.Now()
+	for idx := 0; idx < len(deliveries); idx++ {
+		delivery := deliveries[idx]
+		if delivery.NextAttemptAt.After(now) {
+			continue
+		}
+
+		if !d.wanted(conf, delivery, now) {
+			err := d.stateStore.DeletePendingDelivery(delivery.Id())
+			if err != nil {
+				slog.Error(err.Error())
+			}
+			continue
+		}
+
+		client, ok := conf.NotificationClients[delivery.Notifier]
+		if !ok {
+			delivery.LastError = "notifier is no longer configured"
+			err := d.stateStore.SetDeadLetterDelivery(delivery)
+			if err != nil {
+				slog.Error(err.Error())
+			}
+			continue
+		}
+
+		_, alreadyRetrying := d.retrying.LoadOrStore(delivery.Id(), struct{}{})
+		if alreadyRetrying {
+			continue
+		}
+
+		d.dispatch(notificationJob{
+			notifier:      delivery.Notifier,
+			client:        client,
+			conf:          conf,
+			listing:       delivery.Listing,
+			listingConfig: delivery.ListingConfig,
+			timeout:       conf.NotificationTimeouts[delivery.Notifier],
+			attempts:      delivery.Attempts,
+			retry:         true,
+		})
+	}
+}
+
+// wanted checks whether a notification stored to be sent later is still wanted.
+// It is not wanted if its event has since been snoozed, or is no longer watched.
+func (d *dispatcher) wanted(conf *scannerConfig, delivery store.Delivery, now time.Time) bool {
+	event := delivery.ListingConfig.Event
+	watched := lo.ContainsBy(conf.ListingConfigs, func(listingConfig config.TicketListingConfig) bool {
+		return strings.EqualFold(listingConfig.Event, event)
+	})
+	if !watched {
+		slog.Info(
+			"Not sending notification, as the event is no longer watched.",
+			"notifier", delivery.Notifier,
+			"listingId", delivery.Listing.Id,
+			"wantedEventName", event,
+		)
+		return false
+	}
+
+	snoozed, err := d.stateStore.IsEventSnoozed(event, now)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	if snoozed {
+		slog.Info(
+			"Not sending notification, as the event is snoozed.",
+			"notifier", delivery.Notifier,
+			"listingId", delivery.Listing.Id,
+			"wantedEventName", event,
+		)
+		return false
+	}
+
+	return true
+}
+
+// retryBackoff gets the time to wait before retrying a delivery after a number of failed attempts
+func retryBackoff(attempts int) time.Duration {
+	backoff := initialRetryBackoff
+	for range attempts - 1 {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return backoff
+}
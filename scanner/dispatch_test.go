@@ -0,0 +1,270 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/store"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDispatcher(t *testing.T) *dispatcher {
+	stateStore, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stateStore.Close() })
+
+	return newDispatcher(stateStore)
+}
+
+// testEventConfig is the config of an event watched by test scanner configs
+var testEventConfig = config.TicketListingConfig{Event: "Taylor Swift"}
+
+// testScannerConfig creates a scanner config with notification clients, that watches tickets for test events
+func testScannerConfig(clients map[string]notification.Client) *scannerConfig {
+	return &scannerConfig{TicketScannerConfig: TicketScannerConfig{
+		NotificationClients: clients,
+		ListingConfigs:      []config.TicketListingConfig{testEventConfig, {Event: "Coldplay"}},
+	}}
+}
+
+type failingNotificationClient struct{}
+
+func (failingNotificationClient) SendTicketNotification(
+	context.Context,
+	twigots.TicketListing,
+	config.TicketListingConfig,
+) error {
+	return errors.New("failed")
+}
+
+type stuckNotificationClient struct {
+	errs chan error
+}
+
+func (c stuckNotificationClient) SendTicketNotification(
+	ctx context.Context,
+	_ twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	<-ctx.Done()
+	c.errs <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestDispatcherStopCancelsStuckNotification(t *testing.T) {
+	client := stuckNotificationClient{errs: make(chan error, 1)}
+
+	dispatcher := newTestDispatcher(t)
+	dispatcher.gracePeriod = 100 * time.Millisecond
+	dispatcher.start()
+	dispatcher.dispatch(notificationJob{
+		notifier: "ntfy",
+		client:   client,
+	})
+
+	// Stop should wait for the grace period, then cancel the stuck notification
+	start := time.Now()
+	dispatcher.stop()
+	require.GreaterOrEqual(t, time.Since(start), dispatcher.gracePeriod)
+	require.ErrorIs(t, <-client.errs, context.Canceled)
+}
+
+func TestDispatcherNotificationTimeout(t *testing.T) {
+	client := stuckNotificationClient{errs: make(chan error, 1)}
+
+	dispatcher := newTestDispatcher(t)
+	job := notificationJob{
+		notifier: "ntfy",
+		client:   client,
+		listing:  twigots.TicketListing{Id: "listing"},
+		timeout:  100 * time.Millisecond,
+	}
+
+	// Notification should be cancelled after the timeout of the notifier
+	start := time.Now()
+	dispatcher.send(job)
+	require.ErrorIs(t, <-client.errs, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, time.Since(start), job.timeout)
+	require.Less(t, time.Since(start), defaultNotificationTimeout)
+}
+
+func TestDispatcherFailedDelivery(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+	job := notificationJob{
+		notifier: "ntfy",
+		client:   failingNotificationClient{},
+		listing:  twigots.TicketListing{Id: "listing"},
+	}
+
+	// Failed delivery should be pending
+	dispatcher.send(job)
+	deliveries, err := dispatcher.stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, "failed", deliveries[0].LastError)
+	require.True(t, deliveries[0].NextAttemptAt.After(time.Now()))
+
+	// Delivery that runs out of attempts should be a dead letter
+	job.attempts = maxDeliveryAttempts - 1
+	dispatcher.send(job)
+
+	deliveries, err = dispatcher.stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = dispatcher.stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, maxDeliveryAttempts, deliveries[0].Attempts)
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	delivery := store.Delivery{
+		Notifier:      "ntfy",
+		Listing:       twigots.TicketListing{Id: "listing"},
+		ListingConfig: testEventConfig,
+		Attempts:      1,
+		NextAttemptAt: time.Now(),
+	}
+	require.NoError(t, dispatcher.stateStore.SetPendingDelivery(delivery))
+
+	conf := testScannerConfig(map[string]notification.Client{
+		"ntfy": failingNotificationClient{},
+	})
+	dispatcher.retry(conf)
+
+	job := <-dispatcher.jobs
+	require.Equal(t, "listing", job.listing.Id)
+	require.Equal(t, 1, job.attempts)
+	require.True(t, job.retry)
+
+	// Delivery should not be retried again while it is being retried
+	dispatcher.retry(conf)
+	require.Empty(t, dispatcher.jobs)
+
+	// Delivery for a notifier that is no longer configured should be a dead letter
+	dispatcher.send(job)
+	dispatcher.retry(testScannerConfig(nil))
+
+	deliveries, err := dispatcher.stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries) // Not yet due
+
+	delivery.NextAttemptAt = time.Now()
+	require.NoError(t, dispatcher.stateStore.SetPendingDelivery(delivery))
+	dispatcher.retry(testScannerConfig(nil))
+
+	deliveries, err = dispatcher.stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+}
+
+func TestDispatcherRetryUnwanted(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	// Deliveries for events that are snoozed or no longer watched should be dropped
+	require.NoError(t, dispatcher.stateStore.SnoozeEvent("Taylor Swift", time.Now().Add(time.Hour)))
+	for _, event := range []string{"Taylor Swift", "Blink 182"} {
+		require.NoError(t, dispatcher.stateStore.SetPendingDelivery(store.Delivery{
+			Notifier:      "ntfy",
+			Listing:       twigots.TicketListing{Id: "listing"},
+			ListingConfig: config.TicketListingConfig{Event: event},
+			Attempts:      1,
+			NextAttemptAt: time.Now(),
+		}))
+	}
+
+	dispatcher.retry(testScannerConfig(map[string]notification.Client{
+		"ntfy": successfulNotificationClient{},
+	}))
+	require.Empty(t, dispatcher.jobs)
+
+	deliveries, err := dispatcher.stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = dispatcher.stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+type successfulNotificationClient struct{}
+
+func (successfulNotificationClient) SendTicketNotification(
+	context.Context,
+	twigots.TicketListing,
+	config.TicketListingConfig,
+) error {
+	return nil
+}
+
+func TestDispatcherRetryDeadLetter(t *testing.T) {
+	tests := []struct {
+		name             string
+		client           notification.Client
+		expectedAttempts int // Zero if the delivery should not be pending
+	}{
+		{
+			name:   "success",
+			client: successfulNotificationClient{},
+		},
+		{
+			name:             "failure",
+			client:           failingNotificationClient{},
+			expectedAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := newTestDispatcher(t)
+
+			delivery := store.Delivery{
+				Notifier:      "ntfy",
+				Listing:       twigots.TicketListing{Id: "listing"},
+				ListingConfig: testEventConfig,
+				Attempts:      maxDeliveryAttempts,
+			}
+			require.NoError(t, dispatcher.stateStore.SetDeadLetterDelivery(delivery))
+			require.NoError(t, dispatcher.stateStore.RetryDeadLetterDelivery(delivery.Id()))
+
+			dispatcher.retry(testScannerConfig(map[string]notification.Client{"ntfy": tt.client}))
+
+			// Resent dead letter should be retried with no previous attempts
+			job := <-dispatcher.jobs
+			require.Equal(t, 0, job.attempts)
+			require.True(t, job.retry)
+			dispatcher.send(job)
+
+			_, retrying := dispatcher.retrying.Load(delivery.Id())
+			require.False(t, retrying)
+
+			deliveries, err := dispatcher.stateStore.PendingDeliveries()
+			require.NoError(t, err)
+			if tt.expectedAttempts == 0 {
+				require.Empty(t, deliveries)
+			} else {
+				require.Len(t, deliveries, 1)
+				require.Equal(t, tt.expectedAttempts, deliveries[0].Attempts)
+			}
+
+			deliveries, err = dispatcher.stateStore.DeadLetterDeliveries()
+			require.NoError(t, err)
+			require.Empty(t, deliveries)
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	require.Equal(t, initialRetryBackoff, retryBackoff(1))
+	require.Equal(t, 2*initialRetryBackoff, retryBackoff(2))
+	require.Equal(t, maxRetryBackoff, retryBackoff(maxDeliveryAttempts))
+}
@@ -0,0 +1,213 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/store"
+	"github.com/samber/lo"
+)
+
+// hold stores a notification, so it can be sent when the quiet hours of its notifier end,
+// or the batch window of its event ends if it is batched
+func (d *dispatcher) hold(job notificationJob, until time.Time, batched bool) {
+	slog.Info(
+		"Holding notification.",
+		"notifier", job.notifier,
+		"listingId", job.listing.Id,
+		"until", until,
+	)
+
+	err := d.stateStore.HoldDelivery(store.Delivery{
+		Notifier:      job.notifier,
+		Listing:       job.listing,
+		ListingConfig: job.listingConfig,
+		NextAttemptAt: until,
+		Batched:       batched,
+	})
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// batch holds a notification until the batch window of its event ends, so it can be sent with
+// the other listings for the event found during the window. The window starts when the first listing is found.
+func (d *dispatcher) batch(job notificationJob, window time.Duration, now time.Time) {
+	deliveries, err := d.stateStore.HeldDeliveries()
+	if err != nil {
+		slog.Error(err.Error())
+	}
+
+	// Join the batch of the event if there is one
+	until := now.Add(window)
+	for _, delivery := range deliveries {
+		sameBatch := delivery.Batched &&
+			delivery.Notifier == job.notifier &&
+			strings.EqualFold(delivery.ListingConfig.Event, job.listingConfig.Event)
+		if sameBatch && delivery.NextAttemptAt.Before(until) {
+			until = delivery.NextAttemptAt
+		}
+	}
+
+	d.hold(job, until, true)
+}
+
+// heldGroup is a group of held notifications that are sent together.
+// Notifications held during quiet hours are grouped by notifier,
+// and batched notifications are grouped by notifier and event.
+type heldGroup struct {
+	notifier string
+	event    string // Lower case. Empty if not batched.
+}
+
+// release dispatches the held notifications that are due, for notifiers of a config that are not in quiet hours.
+// Each group of due notifications is sent as one digest if the notifier can send digests,
+// otherwise they are sent one by one in the order they were listed.
+// Held notifications for notifiers that are no longer configured are moved to the dead letters,
+// and those for events that have been snoozed or are no longer watched are dropped.
+func (d *dispatcher) release(conf *scannerConfig, now time.Time) {
+	deliveries, err := d.stateStore.HeldDeliveries()
+	if err != nil {
+		slog.Error(err.Error())
+		return
+	}
+
+	// Deliveries that are already being sent are not released again
+	deliveries = lo.Filter(deliveries, func(delivery store.Delivery, _ int) bool {
+		_, releasing := d.releasing.Load(delivery.Id())
+		return !releasing && !delivery.NextAttemptAt.After(now)
+	})
+
+	// Deliveries that are no longer wanted are dropped
+	deliveries = lo.Filter(deliveries, func(delivery store.Delivery, _ int) bool {
+		if d.wanted(conf, delivery, now) {
+			return true
+		}
+		d.deleteHeld([]store.Delivery{delivery})
+		return false
+	})
+
+	groupDeliveries := lo.GroupBy(deliveries, func(delivery store.Delivery) heldGroup {
+		group := heldGroup{notifier: delivery.Notifier}
+		if delivery.Batched {
+			group.event = strings.ToLower(delivery.ListingConfig.Event)
+		}
+		return group
+	})
+	for group, deliveries := range groupDeliveries {
+		notifier := group.notifier
+		notifierQuietHours, ok := conf.QuietHours[notifier]
+		if ok {
+			_, quiet := notifierQuietHours.EndsAt(now)
+			if quiet {
+				continue
+			}
+		}
+
+		client, ok := conf.NotificationClients[notifier]
+		if !ok {
+			for _, delivery := range deliveries {
+				delivery.LastError = "notifier is no longer configured"
+				err := d.stateStore.SetDeadLetterDelivery(delivery)
+				if err != nil {
+					slog.Error(err.Error())
+				}
+			}
+			d.deleteHeld(deliveries)
+			continue
+		}
+
+		slices.SortFunc(deliveries, func(a, b store.Delivery) int {
+			return a.Listing.CreatedAt.Compare(b.Listing.CreatedAt.Time)
+		})
+
+		slog.Info("Sending held notifications.", "notifier", notifier, "count", len(deliveries))
+		for _, delivery := range deliveries {
+			d.releasing.Store(delivery.Id(), struct{}{})
+		}
+
+		_, isDigestClient := client.(notification.DigestClient)
+		if isDigestClient && len(deliveries) > 1 {
+			d.dispatch(notificationJob{
+				notifier: notifier,
+				client:   client,
+				conf:     conf,
+				timeout:  conf.NotificationTimeouts[notifier],
+				held:     true,
+				digest:   deliveries,
+			})
+		} else {
+			for _, delivery := range deliveries {
+				d.dispatch(notificationJob{
+					notifier:      notifier,
+					client:        client,
+					conf:          conf,
+					listing:       delivery.Listing,
+					listingConfig: delivery.ListingConfig,
+					timeout:       conf.NotificationTimeouts[notifier],
+					held:          true,
+				})
+			}
+		}
+	}
+}
+
+// sendDigest sends held notifications as one digest.
+// If the digest fails to send, each notification is retried on its own.
+func (d *dispatcher) sendDigest(job notificationJob) {
+	for _, delivery := range job.digest {
+		defer d.finishHeld(delivery.Id())
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, job.sendTimeout())
+	defer cancel()
+
+	listings := lo.Map(job.digest, func(delivery store.Delivery, _ int) twigots.TicketListing {
+		return delivery.Listing
+	})
+
+	// Batched notifications are for one event, so can use the ticket listing config of the event
+	var listingConfig config.TicketListingConfig
+	sameEvent := lo.EveryBy(job.digest, func(delivery store.Delivery) bool {
+		return strings.EqualFold(delivery.ListingConfig.Event, job.digest[0].ListingConfig.Event)
+	})
+	if sameEvent {
+		listingConfig = job.digest[0].ListingConfig
+	}
+
+	err := job.client.(notification.DigestClient).SendDigestNotification(ctx, listings, listingConfig)
+	if err != nil {
+		for _, delivery := range job.digest {
+			d.handleFailedDelivery(notificationJob{
+				notifier:      job.notifier,
+				client:        job.client,
+				listing:       delivery.Listing,
+				listingConfig: delivery.ListingConfig,
+			}, err)
+		}
+	}
+}
+
+// finishHeld removes a held delivery once it has been sent, or has failed and been stored to be retried
+func (d *dispatcher) finishHeld(deliveryId string) {
+	err := d.stateStore.DeleteHeldDelivery(deliveryId)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+	d.releasing.Delete(deliveryId)
+}
+
+func (d *dispatcher) deleteHeld(deliveries []store.Delivery) {
+	for _, delivery := range deliveries {
+		err := d.stateStore.DeleteHeldDelivery(delivery.Id())
+		if err != nil {
+			slog.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/stretchr/testify/require"
+)
+
+type digestNotificationClient struct {
+	failingNotificationClient
+	digests chan []twigots.TicketListing
+}
+
+func (c digestNotificationClient) SendDigestNotification(
+	_ context.Context,
+	listings []twigots.TicketListing,
+	_ config.TicketListingConfig,
+) error {
+	c.digests <- listings
+	return nil
+}
+
+func TestDispatcherHoldAndRelease(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	quietHours := map[string]config.QuietHoursConfig{
+		"ntfy":    {Start: "22:00", End: "07:00", Timezone: "UTC"},
+		"webhook": {Start: "22:00", End: "07:00", Timezone: "UTC"},
+	}
+	nightTime := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	morningTime := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+
+	listingTime := time.Date(2025, 1, 1, 22, 30, 0, 0, time.UTC)
+	for _, listingId := range []string{"listing-2", "listing-1"} {
+		listing := twigots.TicketListing{Id: listingId}
+		listing.CreatedAt.Time = listingTime
+		listingTime = listingTime.Add(-time.Minute)
+
+		for _, notifier := range []string{"ntfy", "webhook"} {
+			dispatcher.hold(notificationJob{
+				notifier:      notifier,
+				listing:       listing,
+				listingConfig: testEventConfig,
+			}, morningTime, false)
+		}
+	}
+
+	digestClient := digestNotificationClient{digests: make(chan []twigots.TicketListing, 1)}
+	conf := testScannerConfig(map[string]notification.Client{
+		"ntfy":    digestClient,
+		"webhook": failingNotificationClient{},
+	})
+	conf.QuietHours = quietHours
+
+	// Held notifications should not be released during quiet hours
+	dispatcher.release(conf, nightTime)
+	require.Empty(t, dispatcher.jobs)
+
+	// Held notifications should be sent as a digest by digest clients, and one by one by other clients
+	dispatcher.release(conf, morningTime)
+	require.Len(t, dispatcher.jobs, 3)
+
+	// Held notifications should be kept until they have been sent, but not released again
+	deliveries, err := dispatcher.stateStore.HeldDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 4)
+
+	dispatcher.release(conf, morningTime)
+	require.Len(t, dispatcher.jobs, 3)
+
+	listingIds := map[string][]string{}
+	for range 3 {
+		job := <-dispatcher.jobs
+		if len(job.digest) != 0 {
+			for _, delivery := range job.digest {
+				listingIds[job.notifier] = append(listingIds[job.notifier], delivery.Listing.Id)
+			}
+		} else {
+			listingIds[job.notifier] = append(listingIds[job.notifier], job.listing.Id)
+		}
+		dispatcher.send(job)
+	}
+	require.Equal(t, []string{"listing-1", "listing-2"}, listingIds["ntfy"])
+	require.Equal(t, []string{"listing-1", "listing-2"}, listingIds["webhook"])
+	require.Len(t, <-digestClient.digests, 2)
+
+	// Sent notifications should be removed, and failed notifications should be retried
+	deliveries, err = dispatcher.stateStore.HeldDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = dispatcher.stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, "webhook", deliveries[0].Notifier)
+}
+
+func TestDispatcherBatch(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	// Listings for the same event should be batched until the window of the first listing ends
+	eventConfig := config.TicketListingConfig{Event: "Taylor Swift"}
+	dispatcher.batch(notificationJob{
+		notifier:      "ntfy",
+		listing:       twigots.TicketListing{Id: "listing-1"},
+		listingConfig: eventConfig,
+	}, window, start)
+	dispatcher.batch(notificationJob{
+		notifier:      "ntfy",
+		listing:       twigots.TicketListing{Id: "listing-2"},
+		listingConfig: config.TicketListingConfig{Event: "taylor swift"},
+	}, window, start.Add(30*time.Second))
+
+	// Listings for other events should have their own batch
+	dispatcher.batch(notificationJob{
+		notifier:      "ntfy",
+		listing:       twigots.TicketListing{Id: "listing-3"},
+		listingConfig: config.TicketListingConfig{Event: "Coldplay"},
+	}, window, start.Add(30*time.Second))
+
+	conf := testScannerConfig(map[string]notification.Client{
+		"ntfy": digestNotificationClient{digests: make(chan []twigots.TicketListing, 1)},
+	})
+
+	dispatcher.release(conf, start.Add(59*time.Second))
+	require.Empty(t, dispatcher.jobs)
+
+	dispatcher.release(conf, start.Add(window))
+	require.Len(t, dispatcher.jobs, 1)
+
+	job := <-dispatcher.jobs
+	require.Len(t, job.digest, 2)
+	require.ElementsMatch(
+		t,
+		[]string{"listing-1", "listing-2"},
+		[]string{job.digest[0].Listing.Id, job.digest[1].Listing.Id},
+	)
+
+	// A batch with a single listing should be sent as a normal notification
+	dispatcher.release(conf, start.Add(30*time.Second+window))
+	require.Len(t, dispatcher.jobs, 1)
+
+	job = <-dispatcher.jobs
+	require.Empty(t, job.digest)
+	require.Equal(t, "listing-3", job.listing.Id)
+}
+
+func TestDispatcherBatchWindowsEndingTogether(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	// Batches of different events should be sent separately, even if their windows end at the same time
+	for _, event := range []string{"Taylor Swift", "Coldplay"} {
+		for idx := range 2 {
+			dispatcher.batch(notificationJob{
+				notifier:      "ntfy",
+				listing:       twigots.TicketListing{Id: fmt.Sprintf("%s-%d", event, idx)},
+				listingConfig: config.TicketListingConfig{Event: event},
+			}, window, start)
+		}
+	}
+
+	conf := testScannerConfig(map[string]notification.Client{
+		"ntfy": digestNotificationClient{digests: make(chan []twigots.TicketListing, 1)},
+	})
+	dispatcher.release(conf, start.Add(window))
+	require.Len(t, dispatcher.jobs, 2)
+
+	eventListingIds := map[string][]string{}
+	for range 2 {
+		job := <-dispatcher.jobs
+		require.Len(t, job.digest, 2)
+
+		event := job.digest[0].ListingConfig.Event
+		for _, delivery := range job.digest {
+			require.Equal(t, event, delivery.ListingConfig.Event)
+			eventListingIds[event] = append(eventListingIds[event], delivery.Listing.Id)
+		}
+	}
+	require.ElementsMatch(t, []string{"Taylor Swift-0", "Taylor Swift-1"}, eventListingIds["Taylor Swift"])
+	require.ElementsMatch(t, []string{"Coldplay-0", "Coldplay-1"}, eventListingIds["Coldplay"])
+}
+
+func TestDispatcherReleaseUnwanted(t *testing.T) {
+	dispatcher := newTestDispatcher(t)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	for _, event := range []string{"Taylor Swift", "Coldplay", "Blink 182"} {
+		dispatcher.batch(notificationJob{
+			notifier:      "ntfy",
+			listing:       twigots.TicketListing{Id: "listing"},
+			listingConfig: config.TicketListingConfig{Event: event},
+		}, window, start)
+	}
+
+	// Held notifications for events that are snoozed or no longer watched should be dropped
+	require.NoError(t, dispatcher.stateStore.SnoozeEvent("Taylor Swift", start.Add(time.Hour)))
+	dispatcher.release(testScannerConfig(map[string]notification.Client{
+		"ntfy": successfulNotificationClient{},
+	}), start.Add(window))
+	require.Len(t, dispatcher.jobs, 1)
+
+	job := <-dispatcher.jobs
+	require.Equal(t, "Coldplay", job.listingConfig.Event)
+
+	deliveries, err := dispatcher.stateStore.HeldDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "Coldplay", deliveries[0].ListingConfig.Event)
+}
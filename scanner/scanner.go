@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -15,29 +16,144 @@ import (
 	"github.com/ahobsonsayers/twigots/filter"
 	"github.com/ahobsonsayers/twitchets/config"
 	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/ahobsonsayers/twitchets/store"
 	"github.com/samber/lo"
 )
 
-const maxNumTickets = 250
+const (
+	// Maximum number of tickets to fetch in a single page,
+	// and the maximum number of pages to fetch in each scan
+	maxNumTickets = 250
+	maxNumPages   = 10
 
-func NewTicketScanner(tsc TicketScannerConfig) *TicketScanner {
-	return &TicketScanner{
-		config: tsc,
+	// Maximum time to wait for a page of tickets to be fetched
+	fetchTimeout = 1 * time.Minute
+
+	// How long to remember that a listing has been notified.
+	// Listings older than this will never be fetched again, so there is no need to keep them.
+	notifiedListingRetention = 7 * 24 * time.Hour
+
+	// Number of fetched batches of listings waiting to be matched
+	batchQueueSize = 10
+
+	// How often to check for failed notifications that are due to be retried,
+	// and held notifications that are due to be sent
+	retryCheckInterval = 10 * time.Second
+
+	// Maximum time to wait for ticket listing configs to be announced to notifiers
+	announceTimeout = 1 * time.Minute
+)
+
+// NewTicketScanner creates a scanner.
+// The ticket listing configs are announced to notifiers (in a goroutine), so failures do not stop scanning.
+func NewTicketScanner(tsc TicketScannerConfig, stateStore *store.Store) *TicketScanner {
+	scanner := &TicketScanner{
+		configUpdated:     make(chan struct{}, 1),
+		stateStore:        stateStore,
+		latestTicketTimes: map[twigots.Country]time.Time{},
 	}
+
+	conf := &scannerConfig{TicketScannerConfig: tsc}
+	conf.users.Add(1)
+	scanner.config.Store(conf)
+	go conf.announce()
+
+	return scanner
 }
 
 type TicketScannerConfig struct {
 	TwicketsClient      *twigots.Client
-	NotificationClients map[config.NotificationType]notification.Client
+	Countries           []twigots.Country
+	NotificationClients map[string]notification.Client // Keyed by notifier name
 	ListingConfigs      []config.TicketListingConfig
-	RefetchTime         time.Duration
+
+	// Quiet hours of notifiers, keyed by notifier name.
+	// Notifications are held during quiet hours, and sent when they end.
+	QuietHours map[string]config.QuietHoursConfig
+
+	// Batch windows of notifiers, keyed by notifier name.
+	// Notifications for an event are held until its window ends, and sent together.
+	BatchWindows map[string]time.Duration
+
+	// Maximum time to wait for notifications to be sent, keyed by notifier name.
+	// Notifiers without a timeout use the default.
+	NotificationTimeouts map[string]time.Duration
+
+	// Time between scans, and the maximum random time to add to it
+	ScanInterval time.Duration
+	ScanJitter   time.Duration
+
+	// Maximum time between scans when scanning keeps failing
+	ScanMaxBackoff time.Duration
+
+	// Whether notifications are paused.
+	// Listings are still matched while paused, so they are not notified when resumed.
+	Paused bool
+}
+
+// scannerConfig is a config used by the scanner.
+// Its notification clients are closed once it has been replaced and is no longer used.
+type scannerConfig struct {
+	TicketScannerConfig
+
+	// Users of the config that use its notification clients,
+	// such as notifications waiting to be sent
+	users sync.WaitGroup
+}
+
+// release the config once its notification clients are no longer used
+func (c *scannerConfig) release() {
+	if c != nil {
+		c.users.Done()
+	}
+}
+
+// close the notification clients of the config once all users have released it
+func (c *scannerConfig) close() {
+	c.users.Wait()
+
+	err := notification.CloseNotificationClients(c.NotificationClients)
+	if err != nil {
+		slog.Error(err.Error())
+	}
 }
 
+// announce the ticket listing configs of the config to its notifiers, then release it
+func (c *scannerConfig) announce() {
+	defer c.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), announceTimeout)
+	defer cancel()
+
+	err := notification.AnnounceTicketListingConfigs(ctx, c.NotificationClients, c.ListingConfigs)
+	if err != nil {
+		slog.Error(
+			"error announcing tickets to notifiers",
+			"error", err,
+		)
+	}
+}
+
+// TicketScanner scans for wanted tickets and sends notifications for them.
+//
+// Scanning is a pipeline of stages, each running in their own goroutine(s):
+//   - The fetcher periodically fetches new ticket listings and sends them as a batch to the matcher
+//   - The matcher matches listings in a batch against the listing configs and queues notifications
+//   - The dispatcher's workers send queued notifications. Failed notifications are stored
+//     and periodically retried with backoff, until they run out of attempts and become dead letters
+//
+// Each stage uses the config snapshot that is current when it starts working on something,
+// so the config can be updated at any time without waiting for a stage to finish.
 type TicketScanner struct {
-	config      TicketScannerConfig
-	configMutex sync.Mutex
+	config        atomic.Pointer[scannerConfig]
+	configMu      sync.RWMutex // Held while acquiring or replacing the config
+	configUpdated chan struct{}
+
+	// Fetcher state. Only used by the fetcher, so no need to lock.
+	numFailedScans    int
+	latestTicketTimes map[twigots.Country]time.Time
 
-	latestTicketTime time.Time // No need to lock this
+	stateStore *store.Store
 
 	// Synchronisation
 	running      atomic.Bool
@@ -48,6 +164,12 @@ type TicketScanner struct {
 	cancel context.CancelFunc
 }
 
+// listingBatch is a batch of ticket listings fetched from a country
+type listingBatch struct {
+	country  twigots.Country
+	listings twigots.TicketListings
+}
+
 func (s *TicketScanner) IsRunning() bool   { return s.running.Load() }
 func (s *TicketScanner) WaitUntilStopped() { s.runningWg.Wait() }
 
@@ -60,22 +182,57 @@ func (s *TicketScanner) Start(ctx context.Context) error {
 	s.runningWg.Add(1)
 	defer s.cleanup()
 
-	// Initial ticket scan
-	s.fetchAndProcessTickets()
-
-	// Create ticker
-	ticker := time.NewTicker(s.config.RefetchTime)
-	defer ticker.Stop()
-
 	// Create cancellable context
 	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
 
+	// Start dispatcher and matcher
+	dispatcher := newDispatcher(s.stateStore)
+	dispatcher.start()
+	defer dispatcher.stop()
+
+	batches := make(chan listingBatch, batchQueueSize)
+	var matcherWg sync.WaitGroup
+	matcherWg.Add(1)
+	go func() {
+		defer matcherWg.Done()
+		s.match(batches, dispatcher)
+	}()
+
+	// Stop the matcher before the dispatcher, so all matched listings are queued
+	defer matcherWg.Wait()
+	defer close(batches)
+
+	// Initial ticket scan
+	s.scan(ctx, batches)
+
+	// Create timer. This is reset after every scan,
+	// so the scan interval, jitter and backoff can change
+	timer := time.NewTimer(s.nextScanDelay())
+	defer timer.Stop()
+
+	retryTicker := time.NewTicker(retryCheckInterval)
+	defer retryTicker.Stop()
+
 	// Start ticket scanning
 	for {
 		select {
-		case <-ticker.C:
-			s.fetchAndProcessTickets()
+		case <-timer.C:
+			s.scan(ctx, batches)
+			timer.Reset(s.nextScanDelay())
+
+		case <-s.configUpdated:
+			timer.Reset(s.nextScanDelay())
+
+		case <-retryTicker.C:
+			conf := s.acquireConfig()
+			dispatcher.retry(conf)
+
+			// Keep holding notifications while paused
+			if !conf.Paused {
+				dispatcher.release(conf, time.Now())
+			}
+			conf.release()
 
 		case <-ctx.Done():
 			return ctx.Err()
@@ -83,6 +240,47 @@ func (s *TicketScanner) Start(ctx context.Context) error {
 	}
 }
 
+// scan fetches tickets, keeping track of consecutive failures
+func (s *TicketScanner) scan(ctx context.Context, batches chan<- listingBatch) {
+	err := s.fetchTickets(ctx, batches)
+	if err != nil {
+		// Scan was cancelled because the scanner is stopping, so has not failed
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.numFailedScans++
+		slog.Error(err.Error(), "numFailedScans", s.numFailedScans)
+		return
+	}
+	s.numFailedScans = 0
+}
+
+// nextScanDelay gets the time to wait until the next scan.
+// This is the scan interval plus random jitter. If scans are failing,
+// the interval is doubled for each consecutive failure, up to the max backoff.
+// Failed scans never wait less than the scan interval, even if the max backoff is lower.
+func (s *TicketScanner) nextScanDelay() time.Duration {
+	conf := s.config.Load()
+
+	maxBackoff := max(conf.ScanInterval, conf.ScanMaxBackoff)
+
+	delay := conf.ScanInterval
+	for range s.numFailedScans {
+		delay *= 2
+		if delay >= maxBackoff {
+			delay = maxBackoff
+			break
+		}
+	}
+
+	if conf.ScanJitter > 0 {
+		delay += rand.N(conf.ScanJitter)
+	}
+
+	return delay
+}
+
 func (s *TicketScanner) cleanup() {
 	s.shutdownOnce.Do(func() {
 		// Signal we are no longer running
@@ -91,7 +289,9 @@ func (s *TicketScanner) cleanup() {
 	})
 }
 
-// Stop the worker - blocks until stopped
+// Stop the worker - blocks until stopped.
+// Any fetch in progress is cancelled. Notifications already queued
+// are given a grace period to be sent before they are cancelled.
 func (s *TicketScanner) Stop() {
 	if s.cancel != nil {
 		s.cancel()
@@ -101,57 +301,124 @@ func (s *TicketScanner) Stop() {
 
 // UpdateConfig updates the config of the scanner.
 // This can be called while the scanner is running.
-// Note: RefetchTime cannot be changed while the scanner is running,
-// so, UpdateConfig will ignore any changes to it
-func (s *TicketScanner) UpdateConfig(conf TicketScannerConfig) {
-	s.configMutex.Lock()
-	defer s.configMutex.Unlock()
-	s.config = conf
+// Changes to the scan interval take effect immediately.
+// The ticket listing configs are announced to notifiers (in a goroutine).
+// Notification clients of the previous config are closed once notifications queued with them
+// have been sent, so must not be reused.
+func (s *TicketScanner) UpdateConfig(tsc TicketScannerConfig) {
+	conf := &scannerConfig{TicketScannerConfig: tsc}
+	conf.users.Add(1)
+	go conf.announce()
+
+	s.configMu.Lock()
+	previousConf := s.config.Swap(conf)
+	s.configMu.Unlock()
+	go previousConf.close()
+
+	// Signal config has been updated, so the time until the next scan is recalculated.
+	// Do not block if a signal is already pending.
+	select {
+	case s.configUpdated <- struct{}{}:
+	default:
+	}
 }
 
-func (s *TicketScanner) fetchAndProcessTickets() {
-	// Lock config while fetching and processing tickets,
-	// as the config is used throughout this method
-	s.configMutex.Lock()
-	defer s.configMutex.Unlock()
+// acquireConfig gets the current config.
+// It must be released once its notification clients are no longer used,
+// so they are not closed while in use if the config is replaced.
+func (s *TicketScanner) acquireConfig() *scannerConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
 
-	numTickets := maxNumTickets
-	if s.latestTicketTime.IsZero() {
-		numTickets = 10
+	conf := s.config.Load()
+	conf.users.Add(1)
+	return conf
+}
+
+// fetchTickets fetches new tickets in every country, and sends them to be matched.
+// An error is returned if fetching tickets failed in any country.
+func (s *TicketScanner) fetchTickets(ctx context.Context, batches chan<- listingBatch) error {
+	conf := s.config.Load()
+
+	var errs []error
+	for _, country := range conf.Countries {
+		listings, err := s.fetchCountryTickets(ctx, &conf.TicketScannerConfig, country)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			errs = append(errs, fmt.Errorf("failed to fetch tickets in country %s: %w", country.Value, err))
+			continue
+		}
+
+		if len(listings) == 0 {
+			continue
+		}
+
+		select {
+		case batches <- listingBatch{country: country, listings: listings}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fetchCountryTickets fetches tickets in a country created since the last scan.
+func (s *TicketScanner) fetchCountryTickets(
+	ctx context.Context,
+	conf *TicketScannerConfig,
+	country twigots.Country,
+) (twigots.TicketListings, error) {
+	// Resume from the latest ticket time of a previous run if there is one
+	latestTicketTime, ok := s.latestTicketTimes[country]
+	if !ok {
+		var err error
+		latestTicketTime, err = s.stateStore.LatestTicketTime(country)
+		if err != nil {
+			return nil, err
+		}
+		s.latestTicketTimes[country] = latestTicketTime
 	}
 
 	// Fetch tickets listings from the twickets live feed
-	fetchedListings, err := s.config.TwicketsClient.FetchTicketListings(
-		context.Background(),
-		twigots.FetchTicketListingsInput{
-			// Required
-			Country: twigots.CountryUnitedKingdom,
-			// Optional
-			CreatedBefore: time.Now(),
-			CreatedAfter:  s.latestTicketTime,
-			MaxNumber:     numTickets,
-		},
-	)
+	listings, err := fetchTicketListings(ctx, conf.TwicketsClient, country, latestTicketTime)
 	if err != nil {
-		slog.Error(err.Error())
-		return
+		return nil, err
 	}
 
-	slog.Debug("Fetched tickets.", "numNewTickets", len(fetchedListings))
+	slog.Debug("Fetched tickets.", "country", country.Value, "numNewTickets", len(listings))
 
-	if len(fetchedListings) == 0 {
-		return
+	// Update latest ticket time, so the next scan fetches tickets after it. Most recent ticket is first.
+	// This is only stored by the matcher once the listings have been matched,
+	// so listings are fetched again if the scanner is stopped before then.
+	if len(listings) != 0 {
+		s.latestTicketTimes[country] = listings[0].CreatedAt.Time
 	}
 
-	if len(fetchedListings) == maxNumTickets {
-		slog.Warn("Fetched the max number of tickets allowed per check. It is possible tickets have been missed.")
+	return listings, nil
+}
+
+// match matches batches of listings against the listing configs, and dispatches notifications
+// for matching listings. This blocks until the batches channel is closed.
+func (s *TicketScanner) match(batches <-chan listingBatch, dispatcher *dispatcher) {
+	for batch := range batches {
+		s.matchBatch(batch, dispatcher)
 	}
+}
 
-	// Update latest ticket time. Most recent ticket is first
-	s.latestTicketTime = fetchedListings[0].CreatedAt.Time
+func (s *TicketScanner) matchBatch(batch listingBatch, dispatcher *dispatcher) {
+	conf := s.acquireConfig()
+	defer conf.release()
+
+	// Remove listings that have already been notified.
+	// This can happen if the scanner was stopped while processing listings
+	unnotifiedListings := s.removeNotifiedListings(batch.listings)
 
 	// Filter fetched ticket listings to those wanted
-	filteredListings := filterTicketListings(fetchedListings, s.config.ListingConfigs)
+	filteredListings := filterTicketListings(unnotifiedListings, conf.ListingConfigs)
 	for idx := 0; idx < len(filteredListings); idx++ {
 		matchedListing := filteredListings[idx]
 
@@ -170,23 +437,171 @@ func (s *TicketScanner) fetchAndProcessTickets() {
 			"timeListed", listing.CreatedAt.Local(),
 		)
 
-		// Send notifications
-		for _, notificationType := range listingConfig.Notification {
+		snoozed, err := s.stateStore.IsEventSnoozed(listingConfig.Event, time.Now())
+		if err != nil {
+			slog.Error(err.Error())
+		}
 
-			notificationClient, ok := s.config.NotificationClients[notificationType]
+		// Queue notifications, unless paused or snoozed.
+		// Notifiers are chosen by routing rules. If no notifiers are set, use them all
+		notifiers := listingConfig.ListingNotifiers(listing)
+		if conf.Paused {
+			slog.Info("Not sending notifications, as notifications are paused.")
+			notifiers = nil
+		} else if snoozed {
+			slog.Info("Not sending notifications, as the event is snoozed.", "wantedEventName", listingConfig.Event)
+			notifiers = nil
+		} else if len(notifiers) == 0 {
+			notifiers = lo.Keys(conf.NotificationClients)
+		}
+		for _, notifier := range notifiers {
+			notificationClient, ok := conf.NotificationClients[notifier]
 			if !ok {
 				continue
 			}
 
-			err := notificationClient.SendTicketNotification(listing)
-			if err != nil {
-				slog.Error(
-					"Failed to send notification.",
-					"err", err,
-				)
+			job := notificationJob{
+				notifier:      notifier,
+				client:        notificationClient,
+				conf:          conf,
+				listing:       listing,
+				listingConfig: listingConfig,
+				timeout:       conf.NotificationTimeouts[notifier],
+			}
+
+			// Hold notifications during the quiet hours of the notifier,
+			// unless the listing has a big enough discount to bypass them
+			quietHours, ok := conf.QuietHours[notifier]
+			if ok && !quietHours.Bypasses(listing) {
+				endsAt, quiet := quietHours.EndsAt(time.Now())
+				if quiet {
+					dispatcher.hold(job, endsAt, false)
+					continue
+				}
+			}
+
+			// Hold notifications until the batch window of the event ends
+			batchWindow := conf.BatchWindows[notifier]
+			if batchWindow > 0 {
+				dispatcher.batch(job, batchWindow, time.Now())
+				continue
+			}
+
+			dispatcher.dispatch(job)
+		}
+
+		err = s.stateStore.SetListingNotified(listing.Id, time.Now())
+		if err != nil {
+			slog.Error(err.Error())
+		}
+	}
+
+	// Store latest ticket time now all listings have been processed. Most recent ticket is first.
+	err := s.stateStore.SetLatestTicketTime(batch.country, batch.listings[0].CreatedAt.Time)
+	if err != nil {
+		slog.Error(err.Error())
+	}
+
+	err = s.stateStore.PruneNotifiedListings(time.Now().Add(-notifiedListingRetention))
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// fetchTicketListings fetches ticket listings in a country created after the latest ticket time.
+// Most recent listings are first.
+//
+// If a page of listings contains the max number of listings, there may be more listings since the
+// latest ticket time. Pages of older listings are then fetched until the latest ticket time is reached,
+// or the max number of pages is reached, and all pages are returned as a single batch.
+func fetchTicketListings(
+	ctx context.Context,
+	client *twigots.Client,
+	country twigots.Country,
+	latestTicketTime time.Time,
+) (twigots.TicketListings, error) {
+	// If there is no latest ticket time, this is the first scan.
+	// Only fetch a few listings, as we do not want to notify for old ones.
+	numTickets := maxNumTickets
+	if latestTicketTime.IsZero() {
+		numTickets = 10
+	}
+
+	listings := make(twigots.TicketListings, 0, numTickets)
+	listingIds := make(map[string]struct{}, numTickets)
+	createdBefore := time.Now()
+	for page := 1; ; page++ {
+		pageCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		pageListings, err := client.FetchTicketListings(
+			pageCtx,
+			twigots.FetchTicketListingsInput{
+				// Required
+				Country: country,
+				// Optional
+				CreatedBefore: createdBefore,
+				CreatedAfter:  latestTicketTime,
+				MaxNumber:     numTickets,
+			},
+		)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		// Add listings, ignoring any on the boundary between pages that have already been fetched
+		for idx := 0; idx < len(pageListings); idx++ {
+			listing := pageListings[idx]
+			if _, ok := listingIds[listing.Id]; ok {
+				continue
 			}
+			listingIds[listing.Id] = struct{}{}
+			listings = append(listings, listing)
+		}
+
+		// If the page was not full, all listings since the latest ticket time have been fetched
+		if latestTicketTime.IsZero() || len(pageListings) < numTickets {
+			break
+		}
+
+		if page == maxNumPages {
+			slog.Warn(
+				"Fetched the max number of pages of tickets allowed per check. It is possible tickets have been missed.",
+				"country", country.Value,
+				"numPages", page,
+			)
+			break
 		}
+
+		// Fetch the page of listings before the oldest listing fetched
+		createdBefore = pageListings[len(pageListings)-1].CreatedAt.Time
+		slog.Debug(
+			"Fetched the max number of tickets in a page. Fetching the previous page.",
+			"country", country.Value,
+			"createdBefore", createdBefore,
+		)
 	}
+
+	return listings, nil
+}
+
+// removeNotifiedListings removes ticket listings that have already been notified
+func (s *TicketScanner) removeNotifiedListings(listings twigots.TicketListings) twigots.TicketListings {
+	unnotifiedListings := make(twigots.TicketListings, 0, len(listings))
+	for idx := 0; idx < len(listings); idx++ {
+		listing := listings[idx]
+
+		notified, err := s.stateStore.IsListingNotified(listing.Id)
+		if err != nil {
+			slog.Error(err.Error())
+		}
+		if notified {
+			slog.Debug("Skipping listing that has already been notified.", "listingId", listing.Id)
+			continue
+		}
+
+		unnotifiedListings = append(unnotifiedListings, listing)
+	}
+	return unnotifiedListings
 }
 
 type matchedListingAndConfig struct {
@@ -221,6 +636,26 @@ func ticketListingMatchesConfig(listing twigots.TicketListing, listingConfig con
 		return false
 	}
 
+	// Check countries
+	listingCountry := listing.Event.Venue.Location.Country
+	if len(listingConfig.Countries) != 0 && !lo.Contains(listingConfig.Countries, listingCountry) {
+
+		wantedCountryStrings := make([]string, 0, len(listingConfig.Countries))
+		for _, country := range listingConfig.Countries {
+			wantedCountryStrings = append(wantedCountryStrings, country.Value)
+		}
+		wantedCountries := strings.Join(wantedCountryStrings, ", ")
+
+		slog.Warn(
+			"Found tickets for a wanted event, but country is not in allowed list.",
+			"wantedEvent", listingConfig.Event,
+			"listingEvent", listing.Event.Name,
+			"wantedCountries", wantedCountries,
+			"listingCountry", listingCountry.Value,
+		)
+		return false
+	}
+
 	// Check regions
 	checkRegions := filter.EventRegion(listingConfig.Regions...)
 	if !checkRegions(listing) {
@@ -0,0 +1,301 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/notification"
+	"github.com/imroc/req/v3"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextScanDelay(t *testing.T) {
+	tests := []struct {
+		name           string
+		interval       time.Duration
+		jitter         time.Duration
+		maxBackoff     time.Duration
+		numFailedScans int
+		minDelay       time.Duration
+		maxDelay       time.Duration // Exclusive if there is jitter
+	}{
+		{
+			name:       "interval",
+			interval:   time.Minute,
+			maxBackoff: 15 * time.Minute,
+			minDelay:   time.Minute,
+			maxDelay:   time.Minute,
+		},
+		{
+			name:       "jitter",
+			interval:   time.Minute,
+			jitter:     10 * time.Second,
+			maxBackoff: 15 * time.Minute,
+			minDelay:   time.Minute,
+			maxDelay:   time.Minute + 10*time.Second,
+		},
+		{
+			name:           "backoff",
+			interval:       time.Minute,
+			maxBackoff:     15 * time.Minute,
+			numFailedScans: 2,
+			minDelay:       4 * time.Minute,
+			maxDelay:       4 * time.Minute,
+		},
+		{
+			name:           "backoff with jitter",
+			interval:       time.Minute,
+			jitter:         10 * time.Second,
+			maxBackoff:     15 * time.Minute,
+			numFailedScans: 3,
+			minDelay:       8 * time.Minute,
+			maxDelay:       8*time.Minute + 10*time.Second,
+		},
+		{
+			name:           "max backoff",
+			interval:       time.Minute,
+			maxBackoff:     15 * time.Minute,
+			numFailedScans: 10,
+			minDelay:       15 * time.Minute,
+			maxDelay:       15 * time.Minute,
+		},
+		{
+			name:           "max backoff less than interval",
+			interval:       20 * time.Minute,
+			maxBackoff:     15 * time.Minute,
+			numFailedScans: 1,
+			minDelay:       20 * time.Minute,
+			maxDelay:       20 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewTicketScanner(TicketScannerConfig{
+				ScanInterval:   tt.interval,
+				ScanJitter:     tt.jitter,
+				ScanMaxBackoff: tt.maxBackoff,
+			}, nil)
+			scanner.numFailedScans = tt.numFailedScans
+
+			for range 100 {
+				delay := scanner.nextScanDelay()
+				require.GreaterOrEqual(t, delay, tt.minDelay)
+				if tt.jitter > 0 {
+					require.Less(t, delay, tt.maxDelay)
+				} else {
+					require.Equal(t, tt.maxDelay, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestNextScanDelayResetAfterSuccess(t *testing.T) {
+	scanner := NewTicketScanner(TicketScannerConfig{
+		ScanInterval:   time.Minute,
+		ScanMaxBackoff: 15 * time.Minute,
+	}, nil)
+
+	scanner.numFailedScans = 3
+	require.Equal(t, 8*time.Minute, scanner.nextScanDelay())
+
+	// A successful scan resets the number of failed scans
+	scanner.scan(t.Context(), nil)
+	require.Equal(t, time.Minute, scanner.nextScanDelay())
+}
+
+// feedStartTime is the creation time of the most recent listing in a fake feed
+var feedStartTime = time.Now().Add(-time.Hour).Truncate(time.Second)
+
+// feedListingTime is the creation time of the listing at the index in a fake feed.
+// Listings are one second apart, most recent first.
+func feedListingTime(idx int) time.Time {
+	return feedStartTime.Add(-time.Duration(idx) * time.Second)
+}
+
+// newFakeFeedClient creates a twickets client that fetches from a fake feed of listings.
+// Like the real feed, each request returns 10 listings created at or before the requested max time.
+func newFakeFeedClient(t *testing.T, numListings int) *twigots.Client {
+	t.Helper()
+
+	listingTimes := make([]time.Time, 0, numListings)
+	for idx := range numListings {
+		listingTimes = append(listingTimes, feedListingTime(idx))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxTime, err := strconv.ParseInt(r.URL.Query().Get("maxTime"), 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		type feedListing struct {
+			Id      string `json:"blockId"`
+			Created string `json:"created"`
+		}
+		type feedData struct {
+			Listing feedListing `json:"catalogBlockSummary"`
+		}
+		responseData := make([]feedData, 0, 10)
+		for idx, listingTime := range listingTimes {
+			if listingTime.UnixMilli() > maxTime {
+				continue
+			}
+			responseData = append(responseData, feedData{
+				Listing: feedListing{
+					Id:      strconv.Itoa(idx),
+					Created: strconv.FormatInt(listingTime.UnixMilli(), 10),
+				},
+			})
+			if len(responseData) == 10 {
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"responseData": responseData})
+	}))
+	t.Cleanup(server.Close)
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// Send requests to the fake feed instead of twickets
+	fakeFeedOpt := func(client *req.Client) error {
+		client.WrapRoundTripFunc(func(rt req.RoundTripper) req.RoundTripFunc {
+			return func(request *req.Request) (*req.Response, error) {
+				request.URL.Scheme = serverUrl.Scheme
+				request.URL.Host = serverUrl.Host
+				return rt.RoundTrip(request)
+			}
+		})
+		return nil
+	}
+
+	client, err := twigots.NewClient("test", fakeFeedOpt)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestFetchTicketListings(t *testing.T) {
+	tests := []struct {
+		name             string
+		numListings      int
+		latestTicketTime time.Time
+		expectedIds      []string // Nil if the number of pages is capped
+	}{
+		{
+			name:        "first scan",
+			numListings: 100,
+			expectedIds: feedListingIds(0, 10),
+		},
+		{
+			name:             "single page",
+			numListings:      100,
+			latestTicketTime: feedListingTime(50),
+			expectedIds:      feedListingIds(0, 50),
+		},
+		{
+			name:             "multiple pages",
+			numListings:      1000,
+			latestTicketTime: feedListingTime(600),
+			expectedIds:      feedListingIds(0, 600),
+		},
+		{
+			name:             "max pages",
+			numListings:      3000,
+			latestTicketTime: feedListingTime(2900),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeFeedClient(t, tt.numListings)
+
+			listings, err := fetchTicketListings(t.Context(), client, twigots.CountryUnitedKingdom, tt.latestTicketTime)
+			require.NoError(t, err)
+
+			// Listings on the boundary between pages must only be returned once
+			ids := make([]string, 0, len(listings))
+			for _, listing := range listings {
+				ids = append(ids, listing.Id)
+			}
+			require.ElementsMatch(t, lo.Uniq(ids), ids)
+
+			if tt.expectedIds != nil {
+				require.Equal(t, tt.expectedIds, ids)
+				return
+			}
+
+			// Fetching stops at the max number of pages, before the latest ticket time is reached
+			require.NotEmpty(t, listings)
+			require.Less(t, len(listings), maxNumTickets*maxNumPages)
+			require.True(t, listings[len(listings)-1].CreatedAt.After(tt.latestTicketTime))
+			require.Equal(t, feedListingIds(0, len(listings)), ids)
+		})
+	}
+}
+
+// feedListingIds returns the ids of the listings in a fake feed from the start index to the end index (exclusive).
+func feedListingIds(start, end int) []string {
+	ids := make([]string, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		ids = append(ids, fmt.Sprint(idx))
+	}
+	return ids
+}
+
+type closingNotificationClient struct {
+	successfulNotificationClient
+	closed chan struct{}
+}
+
+func (c closingNotificationClient) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestUpdateConfigClosesClientsOnceUnused(t *testing.T) {
+	client := closingNotificationClient{closed: make(chan struct{})}
+	scanner := NewTicketScanner(TicketScannerConfig{
+		NotificationClients: map[string]notification.Client{"mqtt": client},
+	}, nil)
+
+	// Queue a notification with the client of the current config
+	dispatcher := newTestDispatcher(t)
+	conf := scanner.acquireConfig()
+	dispatcher.dispatch(notificationJob{
+		notifier: "mqtt",
+		client:   conf.NotificationClients["mqtt"],
+		conf:     conf,
+		listing:  twigots.TicketListing{Id: "listing"},
+	})
+	conf.release()
+
+	// Client should not be closed until the queued notification has been sent
+	scanner.UpdateConfig(TicketScannerConfig{})
+	require.Never(t, func() bool {
+		select {
+		case <-client.closed:
+			return true
+		default:
+			return false
+		}
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	dispatcher.send(<-dispatcher.jobs)
+	select {
+	case <-client.closed:
+	case <-time.After(time.Second):
+		require.Fail(t, "client was not closed")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/ahobsonsayers/twitchets/store"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/go-chi/chi/v5"
@@ -17,7 +18,7 @@ import (
 	oapimiddleware "github.com/oapi-codegen/nethttp-middleware"
 )
 
-func Start(port int, frontendFS fs.FS, configPath string) error {
+func Start(port int, frontendFS fs.FS, configPath string, stateStore *store.Store) error {
 	address := fmt.Sprintf("0.0.0.0:%d", port)
 
 	// Create middlewares
@@ -42,7 +43,7 @@ func Start(port int, frontendFS fs.FS, configPath string) error {
 	// Create api router and mount
 	apiRouter := chi.NewRouter()
 	apiRouter.Use(openapiValidationMiddleware)
-	apiHandler := HandlerFromMux(NewServer(configPath), apiRouter)
+	apiHandler := HandlerFromMux(NewServer(configPath, stateStore), apiRouter)
 	router.Mount("/api", apiHandler)
 
 	// Start listening
@@ -14,13 +14,35 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	externalRef0 "github.com/ahobsonsayers/twitchets/config"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 )
 
+// DeadLetter defines model for DeadLetter.
+type DeadLetter struct {
+	Id string `json:"id"`
+
+	// Notifier Notifier that failed to send the notification
+	Notifier  string `json:"notifier"`
+	ListingId string `json:"listingId"`
+	EventName string `json:"eventName"`
+
+	// Link Link to the ticket listing
+	Link string `json:"link"`
+
+	// Attempts Number of attempts made to send the notification
+	Attempts int `json:"attempts"`
+
+	// LastError Error of the last attempt to send the notification
+	LastError     string    `json:"lastError"`
+	LastAttemptAt time.Time `json:"lastAttemptAt"`
+}
+
 // PutConfigJSONRequestBody defines body for PutConfig for application/json ContentType.
 type PutConfigJSONRequestBody = externalRef0.Config
 
@@ -32,6 +54,12 @@ type ServerInterface interface {
 	// Update configuration
 	// (PUT /config)
 	PutConfig(w http.ResponseWriter, r *http.Request)
+	// Get dead letter notifications
+	// (GET /notifications/dead-letters)
+	GetNotificationsDeadLetters(w http.ResponseWriter, r *http.Request)
+	// Resend a dead letter notification
+	// (POST /notifications/dead-letters/{id}/resend)
+	PostNotificationsDeadLettersIdResend(w http.ResponseWriter, r *http.Request, id string)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
@@ -50,6 +78,18 @@ func (_ Unimplemented) PutConfig(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Get dead letter notifications
+// (GET /notifications/dead-letters)
+func (_ Unimplemented) GetNotificationsDeadLetters(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Resend a dead letter notification
+// (POST /notifications/dead-letters/{id}/resend)
+func (_ Unimplemented) PostNotificationsDeadLettersIdResend(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -87,6 +127,45 @@ func (siw *ServerInterfaceWrapper) PutConfig(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
+// GetNotificationsDeadLetters operation middleware
+func (siw *ServerInterfaceWrapper) GetNotificationsDeadLetters(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetNotificationsDeadLetters(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostNotificationsDeadLettersIdResend operation middleware
+func (siw *ServerInterfaceWrapper) PostNotificationsDeadLettersIdResend(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostNotificationsDeadLettersIdResend(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -206,6 +285,12 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/config", wrapper.PutConfig)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/notifications/dead-letters", wrapper.GetNotificationsDeadLetters)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/notifications/dead-letters/{id}/resend", wrapper.PostNotificationsDeadLettersIdResend)
+	})
 
 	return r
 }
@@ -266,6 +351,62 @@ func (response PutConfig500Response) VisitPutConfigResponse(w http.ResponseWrite
 	return nil
 }
 
+type GetNotificationsDeadLettersRequestObject struct {
+}
+
+type GetNotificationsDeadLettersResponseObject interface {
+	VisitGetNotificationsDeadLettersResponse(w http.ResponseWriter) error
+}
+
+type GetNotificationsDeadLetters200JSONResponse []DeadLetter
+
+func (response GetNotificationsDeadLetters200JSONResponse) VisitGetNotificationsDeadLettersResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetNotificationsDeadLetters500Response struct {
+}
+
+func (response GetNotificationsDeadLetters500Response) VisitGetNotificationsDeadLettersResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
+type PostNotificationsDeadLettersIdResendRequestObject struct {
+	Id string `json:"id"`
+}
+
+type PostNotificationsDeadLettersIdResendResponseObject interface {
+	VisitPostNotificationsDeadLettersIdResendResponse(w http.ResponseWriter) error
+}
+
+type PostNotificationsDeadLettersIdResend202Response struct {
+}
+
+func (response PostNotificationsDeadLettersIdResend202Response) VisitPostNotificationsDeadLettersIdResendResponse(w http.ResponseWriter) error {
+	w.WriteHeader(202)
+	return nil
+}
+
+type PostNotificationsDeadLettersIdResend404Response struct {
+}
+
+func (response PostNotificationsDeadLettersIdResend404Response) VisitPostNotificationsDeadLettersIdResendResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostNotificationsDeadLettersIdResend500Response struct {
+}
+
+func (response PostNotificationsDeadLettersIdResend500Response) VisitPostNotificationsDeadLettersIdResendResponse(w http.ResponseWriter) error {
+	w.WriteHeader(500)
+	return nil
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 	// Get current configuration
@@ -274,6 +415,12 @@ type StrictServerInterface interface {
 	// Update configuration
 	// (PUT /config)
 	PutConfig(ctx context.Context, request PutConfigRequestObject) (PutConfigResponseObject, error)
+	// Get dead letter notifications
+	// (GET /notifications/dead-letters)
+	GetNotificationsDeadLetters(ctx context.Context, request GetNotificationsDeadLettersRequestObject) (GetNotificationsDeadLettersResponseObject, error)
+	// Resend a dead letter notification
+	// (POST /notifications/dead-letters/{id}/resend)
+	PostNotificationsDeadLettersIdResend(ctx context.Context, request PostNotificationsDeadLettersIdResendRequestObject) (PostNotificationsDeadLettersIdResendResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -360,43 +507,159 @@ func (sh *strictHandler) PutConfig(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetNotificationsDeadLetters operation middleware
+func (sh *strictHandler) GetNotificationsDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var request GetNotificationsDeadLettersRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetNotificationsDeadLetters(ctx, request.(GetNotificationsDeadLettersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetNotificationsDeadLetters")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetNotificationsDeadLettersResponseObject); ok {
+		if err := validResponse.VisitGetNotificationsDeadLettersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostNotificationsDeadLettersIdResend operation middleware
+func (sh *strictHandler) PostNotificationsDeadLettersIdResend(w http.ResponseWriter, r *http.Request, id string) {
+	var request PostNotificationsDeadLettersIdResendRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostNotificationsDeadLettersIdResend(ctx, request.(PostNotificationsDeadLettersIdResendRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostNotificationsDeadLettersIdResend")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostNotificationsDeadLettersIdResendResponseObject); ok {
+		if err := validResponse.VisitPostNotificationsDeadLettersIdResendResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RZ23LjuBH9lQ42D3aVbt6ZvQyf4ovGpYotO/a4XKmRKwWRLQo7IMDBxbYypa/Jn+TL",
-	"UgBIiqIoj+T1Zp8kgQ10n+5Gdx/qG4lllkuBwmgSfSM6nmNG/ddMJsj1v06lmLHULeRK5qgMQ/+Y5uzv",
-	"uHDfEtSxYrlhUpCI3Az/cTe6GZ5FcIsIN8Pjs8thL0tgJhUkaCjjGqSAuXwCI0FODWWCdIhZ5Egioo1i",
-	"IiUd8txNZVfQzC0eX4+cKrcoVYKKREfLDomlFUZ5C/6qcEYi8kN/BaZfIOlXMIL0skNmnCrUkj+iUneK",
-	"b0K4u7kAOYOPTu42yEGu5PMCNKpHVB7LdJFTrZlI4ZRLm/hD4eDKn0H54TZIbrGrv7C8KwvRbi6ZMA6V",
-	"URZrIN8tOyTlckq9iZTzqxmJPu+E9txv+8TiL2gumDZMpEUclw/rvq1LFiI1E35adoiQhs1YTINzdlI/",
-	"rm0p9XaI8Up88jCDmd7xsFYUlXepUnTRyJc6Gl2H8/Ny2SEKv1qmMCHR5zKJV8nUgFsFYGX9Q6VaTn/D",
-	"2DhbGjm2kVDFA4hlgr2JOLVKoTB8AVLwBZyfANOgbZ5LZTDpTZxeFDZzFp6f1DRuphKJiHliqTS6d1pB",
-	"WCUay9yZ/vJSMycRSZmZ22kvllmfzuVUS6HpApXuF6eQ5QrO9iTaALhVFBTmCrWLK8R+xSrvWtBonJgG",
-	"M6cGaJ7zhSsIlHMIrgYeDtITYQVHrQGfc85i5h3nLiVLEhQwXQAFnWPswlbuXdPVm4hjsSg1gpCmlMcE",
-	"nhjnYDWCmSMkOKOWmxCC9XqXMO2TZBP8JRMssxmUEnDARMxt4nTNEA9duXOHS8VSJiiHXLEYgWqgkKOK",
-	"URia4kScBeUROGPbDhOyWj4MJs6kyqghEUmknXJcFR1hsymqxr24ZOKsRLF/OXq/7BB8RGFuWcY4Vcy0",
-	"ZPrQCYDTB7oSg4yaeO4QHAx6A+jCUW/gAFSAB70PcEA5l0/a19aMCancKW5PwmYzVChi1If7gN4Dmmsn",
-	"GX0O+XvtotMSZfrsoxyCl6MqU60ZbSYgl1YkGg7++5/DRlj97lfFbs28kYj5R8RXQG2r6OtA68XbNzwW",
-	"o3ZX0+q1JOW8umWYVIIB2z71va7vk/PAluK+B8ifHUibfVo1nPYbGzzten1R3aFsDi6KRf1pRHBjT0Bc",
-	"WOzsSV+Vg+98a0qZFC0Gn6NMFc3nLIZCxgVEI1Xx3N+YlS1rASqF2Wy96vUm4qPl3EOMYG5MrqN+/3vN",
-	"oT/lctrPKBN9LkPAeqn84eKXD92LD4O9A3/jjXuDcP+4XG5vyucuvRbbplgjv2DLHTjOXacJV8CLwEzJ",
-	"DMJZraNdZUqH2LaZ8p/SqmJ/OUXe3Vy8dNRRc1hx53YKi1+YQlqGr53u+HrPbOmAaUC/W3DX3O6uo9l5",
-	"69jUNxrkmCqa7TotFuLlAcvdHOULT/StGru8uZ0Scs2KjWGs/by9p9zvVsGaHrM9nx0neZIq2Qz5dfHE",
-	"lwtqzRyFcfp8rdOGuv76tvzFDQxG5izeNGY0A+u5U1l6nL97et6BjH5B0Fa5eYxpNxhbwb5adPVuIe1f",
-	"Xnv5IKaiGvNyO+UsrnADNU1DXr6YHWI1qtCZN8hj8eT/5+d32wqFc/0LhaKov5sM3q8HsgITcS21ZlOO",
-	"8Ei5RQ1UYTQRXTg/ubiK4EKKxFUL9/v2KoJbac28+Hlf/IR71KZYG5ZrQ1quXY4iuGQJpyLRYWV4HPnn",
-	"cCxSzmhYHF9FMJaqPH08LH7WThrfl2s1jacR3MbSuOPDyv1xBPeUY6FsPCo2oRIwUhgE1yjYxRXpEIcv",
-	"fNyHj6H/uBz5j+Gx/xgHkXF4Ni4kT/3HfSEy2pXRFQF6M0J3sxov3qBPr85t1NyNohTPqRklW3qiewij",
-	"M5AKUiVtXi60z1S1m76ldZ+jCc36byfSfKQurI6AlTbu1XGDik4J4IW7tBNL3osfu/LRZLUrRny6Jluw",
-	"YdQQXlc0W/knCU6JAdoq4GorM7pkvx1XJ/0Vn5AJgQPMcrOA4K7DYJf/XhQEJ/j5oRTzuRGknLU1me6R",
-	"XxU2Q8Xi6sHvIdo7EuurpnuKNwE9qBxjZIkeDmiNfh96Z0D36C34do2CeSL9En3+XgN6Wya+1UM7o65d",
-	"zD+MSb8ijl7H7wviVuJdJ5sNRr3Xy9r1uXH50NmLkO/rlHbaXriICqhdY/j8EDy2+0SSucaSu3ycUa6r",
-	"tX+jkhtzyy/fYenj3dj5K7IiBLqRFtu7zft1ar5XcMueuxnWXTn9q0JcnPhnhHVjHA21brN5OjkmZtLz",
-	"cGa4f3X/xEw8d7Fe73DH127MekSlg/OOeoPewPVfmaOgOSMRedcb9N67bkLN3MepH1d9OEXTNucaxfAx",
-	"UII4vJOHlQFrLZJ4TeG7G2XcmFH9XaJQ51Lo0L5+HAz8zCOFKUo8Xb1M6P+mQ4EIWbLzf1cFl102c+jW",
-	"xjFqPbMu4sEI55Sfgg0NzuViKnz2+NcPqJRUfjzUNsuoWhTDU+mJdfzLDsltixPv8oSa4MJdXXdt6677",
-	"alGbE5ks/kivrdLRJeuyPWTN/2zq+Wc9zAR05XDuJ+D37Z5+pJwlmx58dVwKLzcO9DJ+k/blaP3cCxlT",
-	"Dgk+Ipd55iIaZElBkomjvFHfv8rjc6lN9Ovg1wFZPiz/FwAA//9ncC/+FR4AAA==",
+	"H4sIAAAAAAAC/+x963LbOJbwq+DjfD+SKVqynbg7UdVWjeO4O561HbftbHar3dUFkUcS2iSgAKAVTZef",
+	"Zt9kn2zrHAAUKZK6OHL3TtX8sg3iem44V/j3KFH5VEmQ1kSD3yOTTCDn9Ot74Ok5WAsa/5pqNQVtBdA3",
+	"bi3kUzckBZNoMbVCyWgQXRb5EDRTIxb6sJynwKxiBmTK7ASYVFaMRMJpSBzZ+RSiQSSkhTHoKI6+7imd",
+	"4rLfPcYRPIC0lzwHXMt3NVYLOa72fP0YRyJd2eXgMY4ybuyx29exxd4jpXNuo0GUcgt7VuQQxd1TvPFT",
+	"nGqtdPPs1IxHx0NivwCDDU7fXOx7XEzI++Y650Le45Q4mxXJPViWCWPd+M75jmg+6na2GlKvHuPI7RJa",
+	"TnnpvzA74ZaNuMggfdIBDx8f40jDl0JoSKPBz4jAysLV3VbJwEMlXhBhFSnLOP6l3IAa/gaJjR7jKFcp",
+	"ZObXEyVHYtxC3FPx7zBvHvz69KdPZ9en7wfsBoBdnx6/vzjt5SkbKc1SsFxkhinJJmqG4FBDy0X7+cdq",
+	"TxJBR8dXZ7jUEpUmqpBW+93U93CcpgJ/5RkrexHwEy5pI44eTMx4puTYiBR8xzl78XHqhr7s3eHGhIWc",
+	"lvj/GkbRIPpLfyEL+l4Q9EtY0RzRY3kerjWfh+Ng2565F9M95dfYmypkaB0NrC5gibj8jrZfe5RxDUZl",
+	"D6D1J5014fPp+hwZ8Afsd+P6salWX+fMgH4ATTAazqfcGCHH7CRTRUqTVqDThbMtDom8O87UkNMWeZZ9",
+	"HEWDnzc67Y807JbQeO4YwBPq4y914qn29F2qhHRYcrFnxW13clkZXNlCmzRwnQjGIgETMyUB8QA8mTCE",
+	"W534TrHZd2bCMM4C1zM8W8r4yIJmwho3GHrjHpN2NO/dyU8Gyt5E+gltrdDAcqUBpZKk5cP8Xh4bngPN",
+	"5oh/c4zmgmTJPBqMeGbKtn+AVg28H+xXRGfb7Uink20wq0MIOxqWF8ayIbBCii8FxEzIJCtSJFySs9RH",
+	"jTrmK+GSMiFrfbZn/yDzAxm0SwFPl/XO5gnsc4BScMoLA2kThp8nYCdIKZUTGYYs7EbUAenYw303VmQZ",
+	"iUoJTmzPJiIL42I2LCyTqmViA9JSfzuB3EHPn3+oVAZcPuGMbx/jCLdyhl8feIss+6BmTI0syJqAlzBb",
+	"uvSN449X+yZmB7i9C0823LIMuLHsyPTYyYTLMRhm+T0wGI0gsWwm7EQVlnGmwViube9OvocRLzI7YAf1",
+	"kzZlYTSI3hc6XPPbnv+1P//fRVAx66e/4F9FXuRMc5mqnKFqxniaOl2D5AqBRHjwxdjMH5RIqV0ik3DL",
+	"NIyLjGsa7uF0sG+qx7xU7DfawrOe9sif9oJ/fceTezUadZ+YjmoVm3GBaLQzAEmHMmw2Abk43z3A1JAG",
+	"JuQYaZ30wRyWBqWqGGZgvFAl0CVKGkgKKx6AxhcaYlZMnVIpUGWnrdTI4eh56QF1fWO5hStuJ03gYGsQ",
+	"5SNkWmJ1ZAyriLW5BaeRktA0hX7Aw3m6NvGdNEUyYdwsuIZ6T/gDMJ5p4OmcDRFoXnxX5EjMrp2WasJ8",
+	"L6uQobV76ZAJSdubKX2P6EmFhsQqPV8Dtw0BhMaH1+4QPNuI71ZtYqUMr2oVpqZWvFrW2r26vNDqStVn",
+	"sd+VWnhF2d2FSro8dYsm7z+wRKWoDpwUWoO02Zwpmc3Zj++YMMwU06nSFlKHPZBFjqf98V30ywpcRoPI",
+	"zsRYWdM7KcGxwLTIcU4yOYjKo7Gwk2LYS1Te5xM1NEoaPgdt+n6W6HFxnPfCJEqnXWZLYUBLbyc3tY7S",
+	"QsvBGI4XATcxE9JY4GlgrBkMJ0rdk2axW4UYlVE/e6ve7s9W7uDT9XmPnWhArlYSAmvh5GPtb2YD1jEy",
+	"OhtYMsFrPVtlcB4sk25lRyso9DTnIuuC+kirvNVM02BMCXTIyTqkzis2iDJwooxtTnhzcXsVTBjqEa92",
+	"dKCBM1M6bROk7gspE7ywE5AWlR1IWWURs1v04/UXKL/7YNjD3dFHb76n/d3cHl/f3p7fxOz1d0fUcnt+",
+	"s9JlhISGV5sWdr61xUOYvgmjm8bOB2fa0/xABJkoKSHBz8EhUz1QTROt3hfa2sw8pyGCVrZVnYQJDdK0",
+	"qmoS1PHddlVUDN1uwfPJf/lDae31Mpd7fiH6i6PAg2oty99UyGg7OuCyer7endxjUkkYsPdkXbQP7LGP",
+	"ePsUBpwOhjALAFKSzVWhmZpJJsGihtG7k4wdswB8xmXKAtezhGzgbM6GXlFCTd9NK5yoR6AwYVimEp7h",
+	"H7TLQJoD9mk61jxd3iMe7vb8hhXkPQncSUNp1Inr6b9jzxcin2YiERb/elm/SxEmURyFRRErmWlerxW0",
+	"fIWk02unx23+Mj0ucmR13LkupD9PnnPpgVKjvS0YYPv7zy/bpo64/fgtkgTsuw6mT+rovGcmXsEPmxeG",
+	"OjslM5vHRFhCMs7MBLKsS+es3BFoKqjCrjNEKmsiWeGi5ESDkdLABFGRsWo6hbQ0sOqS74RL3NwQGPok",
+	"nedYeu81bSHoH8FzUpWWr/bNs9odDX02oGmFeOj20TVg2dmVaZhqMESbwVUTPDi2YqDw6TSbk1DJsmXD",
+	"/04WMgNjGHx1TIYqLPo8RZqCZMM5UsMUEnRnhLG1tXp38ljOw4pONrn+JDOyzIsjYKnDh0NFnfNWuKtP",
+	"aj5q4DqZVL3USBtlj+Ds8v6ZmIke9LABBPl7ghtb6eBdLBeu0stxxcVTdkCpVzvcn+kBR1mQCkOba2E+",
+	"IYn5Qg/2YuH1GwG8ZMqxjtJiLDAOMNUiAcYN42wKOgFp+RiqAJHz1smkKpu9XF4Ew8hjsOA5SUG9JRPx",
+	"Qsj34RRP0wgppnMjcpHx9nv2FDs4a8SU3VjObTLBE7zY7+2zPXbQ26/pV/u9t+wFzzI1c5doLqQix7Iz",
+	"ykcj0CATMC+3OfQ23ksUFPyr4/krxE63iHXIm0JgiQa2hWRTVcjUsBf/898vl9BKo5+Eu9r2zmSS/QDw",
+	"RKfNcoyhaXtWnNTeZ18YWGLZire67LnEpLu+k1F3lUV+u/CptHOiLEPaXmyxcF0gdrwsXsJMY0ztDqva",
+	"Ldt7TTWMhZItG/4R1Fjz6UQkzPfpFLvX/nsQukIGoeru+yA3h4CU6KYgqVlDWlilRbr+UGQZwWbAJtZO",
+	"zaDfX+fr6A8zNeznXMg+aqV0P43VX86/f7t3/nZ/a4HtjrijiKVWBWL5usjAbG1WXlcHN61KamfJRClT",
+	"hnaWmIWwVzouSW/lC6GudCkJ3FxcA0smkNw7EqVzxGQh1Ccv/anaWKaLzF31fh0nZ/1saET07uTZqNYj",
+	"VWAI89SVcTmnWeJ6REqYMLzqdMeOW5vAwRP6uEI3I325y0qYaqHa75or/2U5oGZictuwffYiF/IlIuQA",
+	"f+dfX3bY90edvF4hqLCRkqJadyOaAT6zGS0sRcHWY9ljKWYKta2ZMMDCHisY3JIHryqn3AEnovSz6h5a",
+	"7pnj6TTzEGLUxWHNUYOHgGsXpuQNCqfYmUAIWMN8qIC5QJ+/krC/hkzxNABgRUJLHBVt3s3/QtvdbSW4",
+	"hj5dn2/lqsR5w+FXWCYXX6ztov2hVvetkbafbm+Z+4jbckacTaaDfr/0DgwO3rx55aPvxmSDfj944whc",
+	"M5TvJGBmxoSPMxga1bz82o3RJBMg7VmbdUxf2Nl7H+yXIXLZwX4lPncTdnkVtPUH0PMrDSPxtc0hlAM7",
+	"NkYYy6VlBNFyEJvSqI7tTlQOPIzczZbfkhs5h3JD78NWuqPpVrFpMcyEmbDVh3FcYZblC3IVuesN4+yO",
+	"krCcaLmLmAFplEu9oegj6f07CqO/eaqz2xH8M/i5v6gWef5TwTN/uYS8lArEkZrR/UcK2H7MDpCnDjsI",
+	"Zn9HquR3JGEoSa2bKiYQJIOZqCJLmRsRdg5peX+0wfHpaP2eJP1UJM293WJzFXphB+TCXi2cn+KpfhZC",
+	"aTip3SpROPUKEd+SltWRo1nPCKp7flr8OGO6oDa82WtqFhpSduOhl7Y60EKGIb1801C27x4meNwMULfU",
+	"5/eF6xm3G4cjV3ZRhikjL/Z1GsWRyXiCLRQsieIo/2It/vkVEgRjYSYoHFe5rqubqYe6V5u1yzOA7sL5",
+	"sc+da8sGw5w750XDyUtLoBpBxb/xI93nhc4Cww+pW5vfb4h891nIVM1auLSaxOIyT3ipcxKTSXcPMAp1",
+	"FDINHmXn8BZyHDtN5DCvC8LzoPr6hCyX2efmchO5rC5cGRcjY0jlQyHJGrKKLN1UjIGCHp8dumOGOCXI",
+	"IFYrRpKLkuG9FbavJKUmYjDGB9Drto3T86zmYjyxjM/4/Fk92AdvSUXB89xCPs24bRFwPypm/ccydUaD",
+	"TEF7UJgY4ZZMAqwaqdzGXV4PoHm2sD86HP20xHDOZmugGxZdBeQqcN8VIqMwRzjMbjSmg+8fF+y+mSCq",
+	"Z2M8Btkw2DzQXBmKcmTDkYsQ2GP8bSKbhNhmQytmBcr69iuUklSdt3YpnhOXJEdeDvBIb/VyhJShNfbC",
+	"k++bUlhvaMH67osJvhQC7AdV6E2N4J/KAYtJ3IWy2fgb7LuzCzOOAutsJSRqt0qZQ7SK+13I2U/3g8jg",
+	"j+Dj15Xz4ZrdiYTcpREmSlouXLooe77z7+h4R5uHbf3VS1dtffdWuUtdWn/DHuQdpsZzx14P9+k8NoPb",
+	"b6ZJmmY5i15ZCpvWO86nYFzepxtTPfEi4rQjjH1XKnTfUH1B2mvTcYzNdVHb0PraaLLQ2XOmHnV6wXxt",
+	"Ttdu69mIQU0VtpLoR9QaBPig30d77m/kE+tiP6z3aCnXwj2UCZ7o9NqhsyjYD5uJZ69+VsyZqlVIN+0q",
+	"W9B2u7pTyPi8WzGnz0sK3nAeSgmWJEKjouDgmSUDFdKIRMlOMuKS4Xc8isGSO8J3/Tg7tdjfPtXRhFTN",
+	"ZQI79yDE3xDNOFhEM45WBjNe7dDb9K+Yx1M9Ybwtt+2Wj00Lfr0VxyUyLOTqN+EFp+Up/4PS3d6sCNQk",
+	"icuMphjNCm5xe77/tdfr1RGLMYjKVdGaAbkbcX7U7YU8G/kkxxDdpvJEM4lZjgVWxuV3CkdjzjKyCpM4",
+	"/99TI0iE0pCORb7PpIQWCualjawzoJ7kC30mWfaqK9q1xhVaY7UmpS2YmTSCmgwpk4gorc5F+1xpdekN",
+	"Q7FBGWrlB5eQ53xUzAAqlzM+95O1ucieKQ2oDv3dZzDh3ZJX0rvWJ6ltlJT23Ns+dNteDXG/8/9jEH+1",
+	"2W3u/CQrkwsakeNy2lWMVPdytOiSD63QfA8hlEUOvPqdbVWHToGpQ2rkMtrdzDtUv7uunaszf+eElcOZ",
+	"KcN2o+cqnNDsCO/jJ6Y0G2tVTNk9zGPSS2WZu1+ul3IzGSqu01WrNRMAjA8PrckAaHibWoLVhfalpM1S",
+	"5wlgkI/76B+Xrva5hscP2KWjSJqbinfdrUH+MjahRUGmrZEEepBh5+KmJvAXXg+WFgjt2sa6CbWcIECn",
+	"dyefTeiCTDsstiUoxkxIdviaGtiHD4OLC+Z25NSm/e8H+/vriJnSXTZYjvqtWfDwcM2CoeDgH0pC+5r/",
+	"qCQg0pIuXCDTavH2aYGE0z9XMlWyA2vnmLTCwmK7kixL7OiA5zC2ght9EmLzLRlqdwWo7E5eKWPEMAP2",
+	"wLPCpdwNsKjmx3fnHwfMndb9ffNxwG5UYSf+z8/+T/YZjPVtp6HtlIe2i7MBuxBpxmVqXMvp8YC+s2M5",
+	"zgR3jZcfMX6lw+yXp/7PykyXn0NbZcWTAbtJlMXpXcvn4wH7zDPwi12e+UGgJTvT4DrWymrPP0ZxhOdz",
+	"Pz67H6f04+KMfpwe049L1+XSfbv0PU/ox2ff5WzTKl2PoJ0V6V4vknN3kKxamXeRQ9qm6ZKJu0UK6b/U",
+	"3zVqxD+h+nvwz6v+7vStorYc684yhGhVUk5tS6uk/FJ2+Facvxi7gv2rMcCGdr6qsJ4GMiETReUvtfp6",
+	"9IsHrwGfih4FJUnMuQCXkOO+H2Ceoa6+jExOuG2TaSHQiaX9lSf0VLMAbcLbM0dx3Nn7hVaeuIY19lMc",
+	"2YkGnrZNefY+iMeR0hhro2S0Fw5e8Ksb+KtIX1b2G7NR2IH3XdKoLqUT/ZZjkJRmQR135IFtPLfnwbYJ",
+	"hrpfyvu0JtITpmDvlGVohLn855iJ4EArC5s78mMrBBoooqf0eHcevi7yIbMu6aAh74fEv0zpjVzgfOkt",
+	"Muzm8soV41XS2Rlu3TFMOxOYGjUKybh/RtClZODxOxzD2yQYIBvv6n1AKsE1K9OVJ1ymWVmbbJxp5x9h",
+	"GCrr4hz4hwM+4oRLvIYXz2aG+qYZxQPWvvizLo4YtrKj1GYKmXBt4EKl28ePA1quyhmat+IPdJFTSCQk",
+	"bHWkNhTGqrwMxC/qxmZaWAvSs3J4t8BPWmfj/FmjzitKQ34ETwx/e6fsD861oWQpmba62da6X5pgH/y+",
+	"HurlVRfAT08qIMwGCwn64fbCF2fcFfv7r5Ih/YChylLX0PctNDjn+j5VM/lwWJniwjf+x6Gb6K84+K/1",
+	"Vxlw1QgrWMP4VWmtG9XAb1X9jmy1XLO+qHc/qfX1te5gmHtxajnF+VYh+4JlvLUDs4ryC3xtO2Wpkdl9",
+	"F91F7AXRHnNHfun2Rb97Ix07/vxL6Ebi7mVpZFX67B1Qqyxy0CIpP6wuo9+K1RdPaDVZfE0F/p38uAxD",
+	"n23RYyX0rAogorrqZnn9SwIdpfQugMF+/uW5X7hJd2yhbQ+NSm29B8LewS6K6ZcfwF5VG7/O7bbbMvtO",
+	"CG186mq05rnK5J+AR1rj25DYWVVfrTj/U8zcJ/F5W03+n8Hqb9YU6l9uVqD/BJpwaF4iim7b8ahenb9d",
+	"kTiMO5C6aVn/k3DsZ/wz0Pr6n6WufmvASl/e/mdA9W0lRfykw7JdeDFCT7RiQFcs3GUXhxqVfW+9d4QN",
+	"gd6YbwR6P7r3esjwLCODBHCPB+EM/mABh+xCBKPPmq6vtCMrmZ6VXp57Jz6eAMVm0pqdgFmYmzVI3UmX",
+	"4qT8I7R+obJMlmsrjF08C7cwOQlDCK2K6eBwvQqaZY+dwHJ/2TRyesoK06ie6tosT1fp/Elp4AgR3Ajg",
+	"uVU6bzxr+vebj5dsyudYxN/MESynFcbPuHgvrz52Ny4nFHsT4Kl/IJ6X/9HhqgaOhsW1FMR3M5BDKfXQ",
+	"IBC0p2Z5ZDzNs2Qg0dD2die1l+gwYizbduHeDTFg48VXT/H/uXcbqvX3bsRYcltQlB3PVi3NMBN+ePTd",
+	"vzkz98PF8cnezYfjw6PvAoYR7UxINoGvpQm8o3ySzlR2q9jVx5vbRtLL1s86NFkGewk5ovc7qUYBjegA",
+	"KFa3gY+vMDj6ANq4rR309nv7SC9qCpJPRTSIXvX2e6/R3uR2QrTVT0oeHLch9hqsFvDgH/9zryOzxQZq",
+	"RnREK7nfz1Lnbin/G4UGM1XSOIo+3N+PyM6V1ls0lcyb/m/G6cPujt/4UTafNt/gj5uC8kxHRcbCJhAo",
+	"R24PSwIfkS955n3TDLRWmoK6pshzrufuVCUk6uenmrIWIH6apu5NctgYdFdFFXTEKu9UOn9OqC2IEWn/",
+	"sR1lyy6FKv0VdMyUmRLgGQWuXrdD+oFnIm1C8Ml48VBemvAxjvo1vuynwNO9DKz1Ync13S/xdP0fHYU0",
+	"IlfVrAv3GL9/xlKD1XNW+ddEDeao2XCLf7JlvpVdNvLZL9ZrBhv/GCZCRDCHiDqY12Gt/7tIH/vkNCSl",
+	"bdr6UPZPBRR4s3QtU0PgmAsZB7UfKZeUpgYWvaKw/KiVhlw9QOp8y+6NzHJNHwNYYm9lOrF/ll67k5HP",
+	"n+fgCPXnZsbl4lz0/7IEtlKiSijMdf9Hq87YcYVQljQLNJyWCO9wzYsSXxDIVV5wDP86GqzarlS+QP+b",
+	"aMiBaQWGHSG7Gdog6NLDUniATE1zpAPXN/J3fYQRx9rbR2/23+xHj788/u8A+WZ6ny1vAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/store"
 )
 
 type Server struct {
 	configPath string
+	stateStore *store.Store
 }
 
 var _ StrictServerInterface = Server{}
@@ -32,9 +35,53 @@ func (s Server) PutConfig(_ context.Context, request PutConfigRequestObject) (Pu
 	return PutConfig200Response{}, nil
 }
 
-func NewServer(configPath string) ServerInterface {
+func (s Server) GetNotificationsDeadLetters(
+	_ context.Context,
+	_ GetNotificationsDeadLettersRequestObject,
+) (GetNotificationsDeadLettersResponseObject, error) {
+	deliveries, err := s.stateStore.DeadLetterDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make(GetNotificationsDeadLetters200JSONResponse, 0, len(deliveries))
+	for idx := 0; idx < len(deliveries); idx++ {
+		delivery := deliveries[idx]
+		deadLetters = append(deadLetters, DeadLetter{
+			Id:            delivery.Id(),
+			Notifier:      delivery.Notifier,
+			ListingId:     delivery.Listing.Id,
+			EventName:     delivery.Listing.Event.Name,
+			Link:          delivery.Listing.URL(),
+			Attempts:      delivery.Attempts,
+			LastError:     delivery.LastError,
+			LastAttemptAt: delivery.LastAttemptAt,
+		})
+	}
+
+	return deadLetters, nil
+}
+
+func (s Server) PostNotificationsDeadLettersIdResend(
+	_ context.Context,
+	request PostNotificationsDeadLettersIdResendRequestObject,
+) (PostNotificationsDeadLettersIdResendResponseObject, error) {
+	// Move the dead letter back to the pending deliveries, so it is retried by the scanner
+	err := s.stateStore.RetryDeadLetterDelivery(request.Id)
+	if err != nil {
+		if errors.Is(err, store.ErrDeliveryNotFound) {
+			return PostNotificationsDeadLettersIdResend404Response{}, nil
+		}
+		return nil, err
+	}
+
+	return PostNotificationsDeadLettersIdResend202Response{}, nil
+}
+
+func NewServer(configPath string, stateStore *store.Store) ServerInterface {
 	server := Server{
 		configPath: configPath,
+		stateStore: stateStore,
 	}
 	return NewStrictHandler(server, nil)
 }
@@ -0,0 +1,285 @@
+package store
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	pendingDeliveriesBucket    = []byte("pendingDeliveries")
+	deadLetterDeliveriesBucket = []byte("deadLetterDeliveries")
+	heldDeliveriesBucket       = []byte("heldDeliveries")
+)
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// deliveryEventCharacters matches characters of events that are not used in delivery ids,
+// so ids can be used in urls
+var deliveryEventCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Delivery is a notification for a ticket listing that failed to be sent by a notifier,
+// or that is being held until the quiet hours of the notifier end.
+type Delivery struct {
+	Notifier      string
+	Listing       twigots.TicketListing
+	ListingConfig config.TicketListingConfig // Config the listing matched
+
+	// Number of failed attempts to send the notification
+	Attempts  int
+	LastError string
+
+	// Time the notification was last attempted, and the time it should next be attempted.
+	// The next attempt time is not used by dead letter deliveries.
+	// For held deliveries, the next attempt time is when the quiet hours they were held in end,
+	// or when the batch window of their event ends.
+	LastAttemptAt time.Time
+	NextAttemptAt time.Time
+
+	// Whether a held delivery is waiting for the batch window of its event to end, rather than quiet hours
+	Batched bool
+}
+
+// Id uniquely identifies a delivery of a listing by a notifier, for the event of the config the listing matched
+func (d Delivery) Id() string {
+	return DeliveryId(d.Listing.Id, d.Notifier, d.ListingConfig.Event)
+}
+
+// DeliveryId gets the id of a delivery of a listing by a notifier, for the event of the config the listing matched.
+// A listing can match the configs of several events, which are delivered separately.
+func DeliveryId(listingId, notifier, event string) string {
+	eventId := deliveryEventCharacters.ReplaceAllString(strings.ToLower(event), "_")
+	eventId = strings.Trim(eventId, "_")
+	if eventId == "" {
+		return fmt.Sprintf("%s-%s", listingId, notifier)
+	}
+	return fmt.Sprintf("%s-%s-%s", listingId, notifier, eventId)
+}
+
+// PendingDeliveries gets all deliveries waiting to be retried.
+func (s *Store) PendingDeliveries() ([]Delivery, error) {
+	deliveries, err := s.deliveries(pendingDeliveriesBucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SetPendingDelivery adds or updates a delivery waiting to be retried.
+func (s *Store) SetPendingDelivery(delivery Delivery) error {
+	err := s.putDelivery(pendingDeliveriesBucket, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to set pending delivery: %w", err)
+	}
+	return nil
+}
+
+// DeletePendingDelivery removes a delivery waiting to be retried.
+// Nothing happens if the delivery does not exist.
+func (s *Store) DeletePendingDelivery(deliveryId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(pendingDeliveriesBucket).Delete([]byte(deliveryId))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pending delivery: %w", err)
+	}
+	return nil
+}
+
+// DeadLetterDeliveries gets all deliveries that ran out of attempts.
+func (s *Store) DeadLetterDeliveries() ([]Delivery, error) {
+	deliveries, err := s.deliveries(deadLetterDeliveriesBucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SetDeadLetterDelivery moves a delivery that ran out of attempts to the dead letter deliveries.
+func (s *Store) SetDeadLetterDelivery(delivery Delivery) error {
+	value, err := encodeDelivery(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to set dead letter delivery: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		deliveryId := []byte(delivery.Id())
+		err := tx.Bucket(pendingDeliveriesBucket).Delete(deliveryId)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(deadLetterDeliveriesBucket).Put(deliveryId, value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set dead letter delivery: %w", err)
+	}
+
+	return nil
+}
+
+// RetryDeadLetterDelivery moves a dead letter delivery back to the pending deliveries,
+// so it is retried as soon as possible with a full set of attempts.
+// ErrDeliveryNotFound is returned if the dead letter delivery does not exist.
+func (s *Store) RetryDeadLetterDelivery(deliveryId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		deadLetterBucket := tx.Bucket(deadLetterDeliveriesBucket)
+
+		value := deadLetterBucket.Get([]byte(deliveryId))
+		if value == nil {
+			return ErrDeliveryNotFound
+		}
+
+		delivery, err := decodeDelivery(value)
+		if err != nil {
+			return err
+		}
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now()
+
+		value, err = encodeDelivery(delivery)
+		if err != nil {
+			return err
+		}
+
+		err = deadLetterBucket.Delete([]byte(deliveryId))
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(pendingDeliveriesBucket).Put([]byte(deliveryId), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry dead letter delivery: %w", err)
+	}
+
+	return nil
+}
+
+// HeldDeliveries gets all deliveries being held until quiet hours end.
+func (s *Store) HeldDeliveries() ([]Delivery, error) {
+	deliveries, err := s.deliveries(heldDeliveriesBucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// HoldDelivery adds a delivery to be held until quiet hours end.
+func (s *Store) HoldDelivery(delivery Delivery) error {
+	err := s.putDelivery(heldDeliveriesBucket, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to hold delivery: %w", err)
+	}
+	return nil
+}
+
+// DeleteHeldDelivery removes a delivery being held until quiet hours end.
+// Nothing happens if the delivery does not exist.
+func (s *Store) DeleteHeldDelivery(deliveryId string) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(heldDeliveriesBucket).Delete([]byte(deliveryId))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete held delivery: %w", err)
+	}
+	return nil
+}
+
+// migrateDeliveryIds stores deliveries under their current id,
+// if they were stored under an id in an older format
+func migrateDeliveryIds(tx *bbolt.Tx) error {
+	for _, bucketName := range [][]byte{pendingDeliveriesBucket, deadLetterDeliveriesBucket, heldDeliveriesBucket} {
+		bucket := tx.Bucket(bucketName)
+
+		// Keys cannot be changed while iterating, so find the deliveries to move first
+		movedDeliveries := map[string][]byte{}
+		err := bucket.ForEach(func(key, value []byte) error {
+			delivery, err := decodeDelivery(value)
+			if err != nil {
+				return err
+			}
+			if delivery.Id() != string(key) {
+				movedDeliveries[string(key)] = value
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for key, value := range movedDeliveries {
+			delivery, err := decodeDelivery(value)
+			if err != nil {
+				return err
+			}
+
+			err = bucket.Delete([]byte(key))
+			if err != nil {
+				return err
+			}
+			err = bucket.Put([]byte(delivery.Id()), value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) deliveries(bucket []byte) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, value []byte) error {
+			delivery, err := decodeDelivery(value)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) putDelivery(bucket []byte, delivery Delivery) error {
+	value, err := encodeDelivery(delivery)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(delivery.Id()), value)
+	})
+}
+
+// encodeDelivery encodes a delivery using gob.
+// Ticket listings cannot be round tripped through JSON, as dates are not marshalled in the format they are unmarshalled.
+func encodeDelivery(delivery Delivery) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(delivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode delivery: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeDelivery(value []byte) (Delivery, error) {
+	var delivery Delivery
+	err := gob.NewDecoder(bytes.NewReader(value)).Decode(&delivery)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("failed to decode delivery: %w", err)
+	}
+	return delivery, nil
+}
@@ -0,0 +1,168 @@
+package store_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/config"
+	"github.com/ahobsonsayers/twitchets/store"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestPendingDeliveries(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	delivery := store.Delivery{
+		Notifier: "ntfy",
+		Listing:  twigots.TicketListing{Id: "listing"},
+		ListingConfig: config.TicketListingConfig{
+			Event:     "Event",
+			Countries: config.Countries{twigots.CountryUnitedKingdom},
+			Regions:   config.Regions{twigots.RegionLondon},
+		},
+		Attempts:      1,
+		LastError:     "failed",
+		NextAttemptAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	require.NoError(t, stateStore.SetPendingDelivery(delivery))
+
+	deliveries, err := stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "listing-ntfy-event", deliveries[0].Id())
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, delivery.ListingConfig, deliveries[0].ListingConfig)
+	require.True(t, delivery.NextAttemptAt.Equal(deliveries[0].NextAttemptAt))
+
+	require.NoError(t, stateStore.DeletePendingDelivery(delivery.Id()))
+
+	deliveries, err = stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestDeadLetterDeliveries(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	delivery := store.Delivery{
+		Notifier: "ntfy",
+		Listing:  twigots.TicketListing{Id: "listing"},
+		Attempts: 5,
+	}
+	require.NoError(t, stateStore.SetPendingDelivery(delivery))
+
+	// Dead letter delivery should be removed from pending deliveries
+	require.NoError(t, stateStore.SetDeadLetterDelivery(delivery))
+
+	deliveries, err := stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	// Retried delivery should be pending with attempts reset
+	require.NoError(t, stateStore.RetryDeadLetterDelivery(delivery.Id()))
+
+	deliveries, err = stateStore.DeadLetterDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, 0, deliveries[0].Attempts)
+
+	err = stateStore.RetryDeadLetterDelivery("unknown")
+	require.ErrorIs(t, err, store.ErrDeliveryNotFound)
+}
+
+func TestHeldDeliveries(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	delivery := store.Delivery{
+		Notifier:      "ntfy",
+		Listing:       twigots.TicketListing{Id: "listing"},
+		NextAttemptAt: time.Date(2025, 1, 2, 7, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, stateStore.HoldDelivery(delivery))
+
+	// Held deliveries should not be retried
+	deliveries, err := stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	deliveries, err = stateStore.HeldDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "listing-ntfy", deliveries[0].Id())
+	require.True(t, delivery.NextAttemptAt.Equal(deliveries[0].NextAttemptAt))
+
+	require.NoError(t, stateStore.DeleteHeldDelivery(delivery.Id()))
+
+	deliveries, err = stateStore.HeldDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestDeliveriesOfEvents(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	// A listing matching the configs of two events should have a delivery for each event
+	for _, event := range []string{"Taylor Swift", "Eras Tour"} {
+		require.NoError(t, stateStore.SetPendingDelivery(store.Delivery{
+			Notifier:      "ntfy",
+			Listing:       twigots.TicketListing{Id: "listing"},
+			ListingConfig: config.TicketListingConfig{Event: event},
+		}))
+	}
+
+	deliveries, err := stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t,
+		[]string{"listing-ntfy-taylor_swift", "listing-ntfy-eras_tour"},
+		[]string{deliveries[0].Id(), deliveries[1].Id()},
+	)
+}
+
+func TestMigrateDeliveryIds(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "state.db")
+	stateStore, err := store.Open(filePath)
+	require.NoError(t, err)
+	require.NoError(t, stateStore.Close())
+
+	// Store a delivery under an id without its event
+	delivery := store.Delivery{
+		Notifier:      "ntfy",
+		Listing:       twigots.TicketListing{Id: "listing"},
+		ListingConfig: config.TicketListingConfig{Event: "Taylor Swift"},
+	}
+	var value bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&value).Encode(delivery))
+
+	db, err := bbolt.Open(filePath, 0o600, nil)
+	require.NoError(t, err)
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte("pendingDeliveries")).Put([]byte("listing-ntfy"), value.Bytes())
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Delivery should be moved to its current id when the store is opened
+	stateStore, err = store.Open(filePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stateStore.Close() })
+
+	require.NoError(t, stateStore.DeletePendingDelivery(delivery.Id()))
+
+	deliveries, err := stateStore.PendingDeliveries()
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
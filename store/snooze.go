@@ -0,0 +1,61 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var snoozedEventsBucket = []byte("snoozedEvents")
+
+// SnoozeEvent stops notifications being sent for an event until a time.
+// Events are matched ignoring case.
+func (s *Store) SnoozeEvent(event string, until time.Time) error {
+	value, err := until.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal snooze time: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(snoozedEventsBucket).Put(snoozedEventKey(event), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to snooze event: %w", err)
+	}
+
+	return nil
+}
+
+// EventSnoozedUntil gets the time notifications for an event are snoozed until.
+// This is the zero time if the event has never been snoozed.
+func (s *Store) EventSnoozedUntil(event string) (time.Time, error) {
+	var until time.Time
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(snoozedEventsBucket).Get(snoozedEventKey(event))
+		if value == nil {
+			return nil
+		}
+		return until.UnmarshalBinary(value)
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get event snooze time: %w", err)
+	}
+
+	return until, nil
+}
+
+// IsEventSnoozed returns whether notifications for an event are snoozed at a time.
+func (s *Store) IsEventSnoozed(event string, at time.Time) (bool, error) {
+	until, err := s.EventSnoozedUntil(event)
+	if err != nil {
+		return false, err
+	}
+
+	return at.Before(until), nil
+}
+
+func snoozedEventKey(event string) []byte {
+	return []byte(strings.ToLower(strings.TrimSpace(event)))
+}
@@ -0,0 +1,29 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnoozeEvent(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	snoozed, err := stateStore.IsEventSnoozed("Taylor Swift", now)
+	require.NoError(t, err)
+	require.False(t, snoozed)
+
+	err = stateStore.SnoozeEvent("Taylor Swift", now.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	// Events should be matched ignoring case
+	snoozed, err = stateStore.IsEventSnoozed("taylor swift", now)
+	require.NoError(t, err)
+	require.True(t, snoozed)
+
+	snoozed, err = stateStore.IsEventSnoozed("Taylor Swift", now.Add(25*time.Hour))
+	require.NoError(t, err)
+	require.False(t, snoozed)
+}
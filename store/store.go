@@ -0,0 +1,161 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	latestTicketTimesBucket = []byte("latestTicketTimes")
+	notifiedListingsBucket  = []byte("notifiedListings")
+)
+
+// Store is an embedded, file backed store of state that must survive restarts.
+type Store struct {
+	db *bbolt.DB
+}
+
+// Open a store at a file path, creating it if it does not exist.
+func Open(filePath string) (*Store, error) {
+	db, err := bbolt.Open(filePath, 0o600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+
+	// Create buckets
+	err = db.Update(func(tx *bbolt.Tx) error {
+		buckets := [][]byte{
+			latestTicketTimesBucket,
+			notifiedListingsBucket,
+			pendingDeliveriesBucket,
+			deadLetterDeliveriesBucket,
+			heldDeliveriesBucket,
+			snoozedEventsBucket,
+		}
+		for _, bucket := range buckets {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return migrateDeliveryIds(tx)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create store buckets: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// LatestTicketTime gets the creation time of the latest ticket listing that has been scanned in a country.
+// This is the zero time if no ticket listings have been scanned.
+func (s *Store) LatestTicketTime(country twigots.Country) (time.Time, error) {
+	var latestTicketTime time.Time
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(latestTicketTimesBucket).Get([]byte(country.Value))
+		if value == nil {
+			return nil
+		}
+		return latestTicketTime.UnmarshalBinary(value)
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest ticket time: %w", err)
+	}
+
+	return latestTicketTime, nil
+}
+
+// SetLatestTicketTime sets the creation time of the latest ticket listing that has been scanned in a country.
+func (s *Store) SetLatestTicketTime(country twigots.Country, latestTicketTime time.Time) error {
+	value, err := latestTicketTime.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal latest ticket time: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(latestTicketTimesBucket).Put([]byte(country.Value), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set latest ticket time: %w", err)
+	}
+
+	return nil
+}
+
+// IsListingNotified returns whether notifications have already been sent for a ticket listing.
+func (s *Store) IsListingNotified(listingId string) (bool, error) {
+	var notified bool
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		notified = tx.Bucket(notifiedListingsBucket).Get([]byte(listingId)) != nil
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check if listing has been notified: %w", err)
+	}
+
+	return notified, nil
+}
+
+// SetListingNotified records that notifications have been sent for a ticket listing.
+func (s *Store) SetListingNotified(listingId string, notifiedAt time.Time) error {
+	value, err := notifiedAt.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal notified time: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(notifiedListingsBucket).Put([]byte(listingId), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set listing as notified: %w", err)
+	}
+
+	return nil
+}
+
+// PruneNotifiedListings removes records of ticket listings that were notified before a time.
+// This stops the store from growing forever.
+func (s *Store) PruneNotifiedListings(before time.Time) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(notifiedListingsBucket)
+
+		// Get listings to prune. Keys cannot be deleted while iterating
+		var prunedListingIds [][]byte
+		err := bucket.ForEach(func(listingId, value []byte) error {
+			var notifiedAt time.Time
+			err := notifiedAt.UnmarshalBinary(value)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal notified time of listing %s: %w", listingId, err)
+			}
+
+			if notifiedAt.Before(before) {
+				prunedListingIds = append(prunedListingIds, listingId)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, listingId := range prunedListingIds {
+			err := bucket.Delete(listingId)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune notified listings: %w", err)
+	}
+
+	return nil
+}
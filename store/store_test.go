@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twitchets/store"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestStore(t *testing.T) *store.Store {
+	stateStore, err := store.Open(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stateStore.Close() })
+	return stateStore
+}
+
+func TestLatestTicketTime(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	latestTicketTime, err := stateStore.LatestTicketTime(twigots.CountryUnitedKingdom)
+	require.NoError(t, err)
+	require.True(t, latestTicketTime.IsZero())
+
+	expectedTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = stateStore.SetLatestTicketTime(twigots.CountryUnitedKingdom, expectedTime)
+	require.NoError(t, err)
+
+	latestTicketTime, err = stateStore.LatestTicketTime(twigots.CountryUnitedKingdom)
+	require.NoError(t, err)
+	require.True(t, expectedTime.Equal(latestTicketTime))
+}
+
+func TestLatestTicketTimePersists(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.db")
+
+	stateStore, err := store.Open(storePath)
+	require.NoError(t, err)
+
+	expectedTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = stateStore.SetLatestTicketTime(twigots.CountryUnitedKingdom, expectedTime)
+	require.NoError(t, err)
+	require.NoError(t, stateStore.Close())
+
+	// Reopen store
+	stateStore, err = store.Open(storePath)
+	require.NoError(t, err)
+	defer stateStore.Close()
+
+	latestTicketTime, err := stateStore.LatestTicketTime(twigots.CountryUnitedKingdom)
+	require.NoError(t, err)
+	require.True(t, expectedTime.Equal(latestTicketTime))
+}
+
+func TestNotifiedListings(t *testing.T) {
+	stateStore := openTestStore(t)
+
+	now := time.Now()
+	require.NoError(t, stateStore.SetListingNotified("old", now.Add(-48*time.Hour)))
+	require.NoError(t, stateStore.SetListingNotified("new", now))
+
+	notified, err := stateStore.IsListingNotified("old")
+	require.NoError(t, err)
+	require.True(t, notified)
+
+	notified, err = stateStore.IsListingNotified("unknown")
+	require.NoError(t, err)
+	require.False(t, notified)
+
+	// Prune listings notified over a day ago
+	err = stateStore.PruneNotifiedListings(now.Add(-24 * time.Hour))
+	require.NoError(t, err)
+
+	notified, err = stateStore.IsListingNotified("old")
+	require.NoError(t, err)
+	require.False(t, notified)
+
+	notified, err = stateStore.IsListingNotified("new")
+	require.NoError(t, err)
+	require.True(t, notified)
+}